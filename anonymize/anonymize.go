@@ -0,0 +1,309 @@
+package anonymize
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Action is what is done to the values a Rule applies to.
+type Action string
+
+const (
+	// Hash replaces strings and numbers with the hex encoded HMAC-SHA256 of
+	// their text, keyed with the salt and truncated to 16 characters.
+	Hash Action = "hash"
+	// Jitter moves locations by a random distance of up to Rule.Distance
+	// meters. A location is either an object with "lon" and "lat" fields or
+	// an array of longitude and latitude.
+	Jitter Action = "jitter"
+	// Scale multiplies numbers by Rule.Factor.
+	Scale Action = "scale"
+	// Drop removes fields and array elements.
+	Drop Action = "drop"
+)
+
+// Rule applies an action to the values at a key.
+type Rule struct {
+	// Key is a [JSONPath]-like key of the values, e.g. "$.stops[].id". Use a
+	// dot (.) to enter nested objects, empty brackets ([]) to enter all
+	// elements of an array and brackets with an index, e.g. [0], to enter a
+	// single element. The leading "$" is optional.
+	//
+	// [JSONPath]: https://goessner.net/articles/JsonPath/
+	Key    string `json:"key"`
+	Action Action `json:"action"`
+	// Distance is the maximum distance in meters locations are moved by with
+	// Jitter.
+	Distance float64 `json:"distance,omitempty"`
+	// Factor is the factor numbers are multiplied by with Scale.
+	Factor float64 `json:"factor,omitempty"`
+}
+
+// Rules configure how a document is anonymized.
+type Rules struct {
+	// Salt is the secret hashes and jitter are derived from. Without it,
+	// hashed values can be recovered by hashing guesses.
+	Salt  string `json:"salt,omitempty"`
+	Rules []Rule `json:"rules"`
+}
+
+// Do returns an anonymized copy of the JSON document, as unmarshalled into an
+// any, applying the rules. Decode the document with json.Decoder.UseNumber,
+// so numbers keep their precision. If several rules match a value, the first
+// one applies. An error is returned, if a rule matches no value, as a mistyped
+// key would leave the values it is meant for as they are.
+func Do(document any, rules Rules) (any, error) {
+	a := anonymizer{
+		salt:    []byte(rules.Salt),
+		rules:   make([]Rule, len(rules.Rules)),
+		keys:    make([][]string, len(rules.Rules)),
+		matched: make([]bool, len(rules.Rules)),
+	}
+	for i, rule := range rules.Rules {
+		switch rule.Action {
+		case Hash, Drop:
+		case Jitter:
+			if rule.Distance < 0 {
+				return nil, fmt.Errorf("key %s: negative distance", rule.Key)
+			}
+		case Scale:
+			if rule.Factor == 0 {
+				return nil, fmt.Errorf("key %s: factor is missing", rule.Key)
+			}
+		default:
+			return nil, fmt.Errorf(
+				"key %s: unknown action %q", rule.Key, rule.Action,
+			)
+		}
+		key := rule.Key
+		if !strings.HasPrefix(key, "$") {
+			key = "$" + key
+		}
+		a.rules[i], a.keys[i] = rule, segments(key)
+	}
+	document, _, err := a.apply([]string{"$"}, document)
+	if err != nil {
+		return nil, err
+	}
+	unmatched := []string{}
+	for i, matched := range a.matched {
+		if !matched {
+			unmatched = append(unmatched, a.rules[i].Key)
+		}
+	}
+	if len(unmatched) > 0 {
+		return nil, fmt.Errorf(
+			"rules matched no value: %s", strings.Join(unmatched, ", "),
+		)
+	}
+	return document, nil
+}
+
+// segments splits a key into the root, the fields and the brackets it
+// consists of, e.g. "$.stops[0].id" into "$", ".stops", "[0]" and ".id".
+func segments(key string) []string {
+	var split []string
+	start := 0
+	for i := 1; i < len(key); i++ {
+		if key[i] == '.' || key[i] == '[' {
+			split = append(split, key[start:i])
+			start = i
+		}
+	}
+	return append(split, key[start:])
+}
+
+type anonymizer struct {
+	salt  []byte
+	rules []Rule
+	// keys are the segments of the keys of the rules.
+	keys [][]string
+	// matched records the rules that matched a value.
+	matched []bool
+}
+
+// rule returns the first rule matching the path, if any. Empty brackets in
+// the key of a rule match any index.
+func (a anonymizer) rule(path []string) (Rule, bool) {
+	for i, key := range a.keys {
+		if len(key) != len(path) {
+			continue
+		}
+		matches := true
+		for j, segment := range key {
+			if segment != path[j] &&
+				(segment != "[]" || !strings.HasPrefix(path[j], "[")) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			a.matched[i] = true
+			return a.rules[i], true
+		}
+	}
+	return Rule{}, false
+}
+
+// apply returns the anonymized value at the path, given as segments of its
+// key. It returns false, if the value is dropped.
+func (a anonymizer) apply(path []string, value any) (any, bool, error) {
+	if rule, ok := a.rule(path); ok {
+		return a.applyRule(rule, value)
+	}
+	// children get their own copy of the path.
+	child := func(segment string) []string {
+		return append(path[:len(path):len(path)], segment)
+	}
+	switch value := value.(type) {
+	case map[string]any:
+		anonymized := make(map[string]any, len(value))
+		for childKey, childValue := range value {
+			childValue, keep, err := a.apply(child("."+childKey), childValue)
+			if err != nil {
+				return nil, false, err
+			}
+			if keep {
+				anonymized[childKey] = childValue
+			}
+		}
+		return anonymized, true, nil
+	case []any:
+		anonymized := make([]any, 0, len(value))
+		for i, childValue := range value {
+			childValue, keep, err := a.apply(
+				child("["+strconv.Itoa(i)+"]"), childValue,
+			)
+			if err != nil {
+				return nil, false, err
+			}
+			if keep {
+				anonymized = append(anonymized, childValue)
+			}
+		}
+		return anonymized, true, nil
+	}
+	return value, true, nil
+}
+
+func (a anonymizer) applyRule(rule Rule, value any) (any, bool, error) {
+	if value == nil || rule.Action == Drop {
+		return value, rule.Action != Drop, nil
+	}
+	switch rule.Action {
+	case Hash:
+		// numbers are hashed by their text, so numeric IDs hash like IDs
+		// given as strings.
+		switch v := value.(type) {
+		case string:
+			return a.hash(v), true, nil
+		case json.Number:
+			return a.hash(v.String()), true, nil
+		case float64:
+			return a.hash(strconv.FormatFloat(v, 'f', -1, 64)), true, nil
+		}
+		return nil, false, fmt.Errorf(
+			"key %s: cannot hash %T", rule.Key, value,
+		)
+	case Scale:
+		f, ok := number(value)
+		if !ok {
+			return nil, false, fmt.Errorf(
+				"key %s: cannot scale %T", rule.Key, value,
+			)
+		}
+		if _, ok := value.(json.Number); ok {
+			return json.Number(
+				strconv.FormatFloat(f*rule.Factor, 'f', -1, 64),
+			), true, nil
+		}
+		return f * rule.Factor, true, nil
+	}
+	location, err := a.jitter(value, rule.Distance)
+	if err != nil {
+		return nil, false, fmt.Errorf("key %s: %w", rule.Key, err)
+	}
+	return location, true, nil
+}
+
+func (a anonymizer) mac(value string) []byte {
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+func (a anonymizer) hash(value string) string {
+	return hex.EncodeToString(a.mac(value))[:16]
+}
+
+// jitter returns the location moved by up to distance meters, in a direction
+// and by a distance derived from the salt and the location.
+func (a anonymizer) jitter(location any, distance float64) (any, error) {
+	switch location := location.(type) {
+	case map[string]any:
+		lon, lonOK := number(location["lon"])
+		lat, latOK := number(location["lat"])
+		if !lonOK || !latOK {
+			break
+		}
+		moved := make(map[string]any, len(location))
+		for k, v := range location {
+			moved[k] = v
+		}
+		moved["lon"], moved["lat"] = a.move(lon, lat, distance)
+		return moved, nil
+	case []any:
+		if len(location) != 2 {
+			break
+		}
+		lon, lonOK := number(location[0])
+		lat, latOK := number(location[1])
+		if !lonOK || !latOK {
+			break
+		}
+		lon, lat = a.move(lon, lat, distance)
+		return []any{lon, lat}, nil
+	}
+	return nil, fmt.Errorf("cannot jitter %v, it is not a location", location)
+}
+
+// number returns the value of a JSON number, decoded as float64 or as
+// json.Number.
+func number(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// move moves the location by up to distance meters. The direction and the
+// distance are drawn from a generator seeded with the MAC of the location, so
+// the same location is always moved the same way. Locations are drawn
+// uniformly from the circle around the location. The moved location is
+// rounded to 6 decimals, about 0.1 meters, so it is the same on all platforms.
+func (a anonymizer) move(lon, lat, distance float64) (float64, float64) {
+	seed := a.mac(fmt.Sprint(lon, ",", lat))
+	r := rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(seed))))
+	meters := distance * math.Sqrt(r.Float64())
+	bearing := 2 * math.Pi * r.Float64()
+	if c := math.Cos(lat * math.Pi / 180); c > 0 {
+		lon += meters * math.Sin(bearing) / (metersPerDegree * c)
+	}
+	lat += meters * math.Cos(bearing) / metersPerDegree
+	return math.Round(lon*1e6) / 1e6, math.Round(lat*1e6) / 1e6
+}
+
+// metersPerDegree is the length of a degree of latitude in meters.
+const metersPerDegree = 6371.0 * 1000.0 * math.Pi / 180.0
@@ -0,0 +1,197 @@
+package anonymize_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nextmv-io/sdk/anonymize"
+	"github.com/nextmv-io/sdk/measure"
+)
+
+func TestDo(t *testing.T) {
+	input := `{
+		"stops": [
+			{"id": "alice", "location": {"lon": 7.6, "lat": 51.9}, "name": "A"},
+			{"id": "bob", "location": {"lon": 7.7, "lat": 52.0}, "name": "B"}
+		],
+		"vehicles": [
+			{"id": "truck", "start": [7.6, 51.9], "capacity": 10}
+		],
+		"precedences": [{"before": "alice", "after": "bob"}]
+	}`
+	var document any
+	if err := json.Unmarshal([]byte(input), &document); err != nil {
+		t.Fatal(err)
+	}
+	rules := anonymize.Rules{
+		Salt: "secret",
+		Rules: []anonymize.Rule{
+			{Key: "$.stops[].id", Action: anonymize.Hash},
+			{Key: ".precedences[].before", Action: anonymize.Hash},
+			{Key: "$.precedences[].after", Action: anonymize.Hash},
+			{Key: "$.stops[].name", Action: anonymize.Drop},
+			{Key: "$.stops[].location", Action: anonymize.Jitter, Distance: 100},
+			{Key: "$.vehicles[].start", Action: anonymize.Jitter, Distance: 100},
+			{Key: "$.vehicles[].capacity", Action: anonymize.Scale, Factor: 2},
+		},
+	}
+	anonymized, err := anonymize.Do(document, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := anonymize.Do(document, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(anonymized, again) {
+		t.Errorf("anonymizing twice differs: %v, %v", anonymized, again)
+	}
+
+	got := anonymized.(map[string]any)
+	stops := got["stops"].([]any)
+	alice := stops[0].(map[string]any)
+	precedence := got["precedences"].([]any)[0].(map[string]any)
+	if alice["id"] == "alice" || alice["id"] != precedence["before"] {
+		t.Errorf("id %v, want hash referenced by %v", alice["id"], precedence)
+	}
+	if _, ok := alice["name"]; ok {
+		t.Errorf("name was not dropped")
+	}
+	location := alice["location"].(map[string]any)
+	moved := measure.HaversineByPoint().Cost(
+		measure.Point{7.6, 51.9},
+		measure.Point{location["lon"].(float64), location["lat"].(float64)},
+	)
+	if moved == 0 || moved > 100 {
+		t.Errorf("location moved by %v meters, want up to 100", moved)
+	}
+	// the same location is moved the same way, wherever it is
+	vehicle := got["vehicles"].([]any)[0].(map[string]any)
+	start := vehicle["start"].([]any)
+	if start[0] != location["lon"] || start[1] != location["lat"] {
+		t.Errorf("start %v, want %v", start, location)
+	}
+	if vehicle["capacity"] != 20.0 {
+		t.Errorf("capacity %v, want 20", vehicle["capacity"])
+	}
+}
+
+func TestDoNumbers(t *testing.T) {
+	input := `{
+		"stops": [{"id": 9007199254740993, "quantity": 9007199254740993}],
+		"precedences": [{"before": 9007199254740993}]
+	}`
+	decoder := json.NewDecoder(strings.NewReader(input))
+	decoder.UseNumber()
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		t.Fatal(err)
+	}
+	rules := anonymize.Rules{
+		Salt: "secret",
+		Rules: []anonymize.Rule{
+			{Key: "$.stops[].id", Action: anonymize.Hash},
+			{Key: "$.precedences[].before", Action: anonymize.Hash},
+			{Key: "$.stops[].quantity", Action: anonymize.Scale, Factor: 1},
+		},
+	}
+	anonymized, err := anonymize.Do(document, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := anonymized.(map[string]any)
+	stop := got["stops"].([]any)[0].(map[string]any)
+	precedence := got["precedences"].([]any)[0].(map[string]any)
+	if _, ok := stop["id"].(string); !ok || stop["id"] != precedence["before"] {
+		t.Errorf("id %v, want hash referenced by %v", stop["id"], precedence)
+	}
+	// a numeric ID hashes like the same ID given as a string
+	hashed, err := anonymize.Do(
+		map[string]any{"id": "9007199254740993"},
+		anonymize.Rules{
+			Salt:  "secret",
+			Rules: []anonymize.Rule{{Key: "$.id", Action: anonymize.Hash}},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := hashed.(map[string]any)["id"]; id != stop["id"] {
+		t.Errorf("numeric id hashed to %v, string id to %v", stop["id"], id)
+	}
+	if quantity, ok := stop["quantity"].(json.Number); !ok {
+		t.Errorf("quantity %T, want json.Number", stop["quantity"])
+	} else if f, _ := quantity.Float64(); f != 9007199254740992 {
+		t.Errorf("quantity %v, want 9007199254740992", quantity)
+	}
+}
+
+func TestDoIndices(t *testing.T) {
+	document := map[string]any{
+		"stops": []any{
+			map[string]any{"id": "a", "tags": []any{"x", "y"}},
+			map[string]any{"id": "b", "tags": []any{"z"}},
+		},
+	}
+	anonymized, err := anonymize.Do(document, anonymize.Rules{
+		Rules: []anonymize.Rule{
+			{Key: "$.stops[0].id", Action: anonymize.Drop},
+			{Key: "$.stops[].tags[1]", Action: anonymize.Drop},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"stops": []any{
+			map[string]any{"tags": []any{"x"}},
+			map[string]any{"id": "b", "tags": []any{"z"}},
+		},
+	}
+	if !reflect.DeepEqual(anonymized, want) {
+		t.Errorf("got %v, want %v", anonymized, want)
+	}
+}
+
+func TestDoErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		rule anonymize.Rule
+	}{
+		{
+			name: "unknown action",
+			rule: anonymize.Rule{Key: "$.a", Action: "encrypt"},
+		},
+		{
+			name: "hash bool",
+			rule: anonymize.Rule{Key: "$.c", Action: anonymize.Hash},
+		},
+		{
+			name: "jitter number",
+			rule: anonymize.Rule{
+				Key: "$.a", Action: anonymize.Jitter, Distance: 1,
+			},
+		},
+		{
+			name: "missing factor",
+			rule: anonymize.Rule{Key: "$.a", Action: anonymize.Scale},
+		},
+		{
+			name: "no value matched",
+			rule: anonymize.Rule{Key: "$.b", Action: anonymize.Drop},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := anonymize.Do(
+				map[string]any{"a": 1.0, "c": true},
+				anonymize.Rules{Rules: []anonymize.Rule{tt.rule}},
+			)
+			if err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
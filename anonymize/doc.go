@@ -0,0 +1,19 @@
+/*
+Package anonymize contains functions to anonymize JSON documents, such as the
+inputs of decision models, so they can be shared. [Do] applies [Rules] to a
+document. Each [Rule] applies an [Action] to the values at a [JSONPath]-like
+key:
+
+  - [Hash] replaces strings and numbers with their salted hash.
+  - [Jitter] moves locations by a random distance up to a bound.
+  - [Scale] multiplies numbers by a factor.
+  - [Drop] removes fields and array elements.
+
+Hashes and jitter only depend on the salt and the original value, so an ID is
+replaced by the same hash wherever it is referenced, and a location by the same
+location. A rule matching no value is an error, so a mistyped key does not
+leave the values it is meant for as they are.
+
+[JSONPath]: https://goessner.net/articles/JsonPath/
+*/
+package anonymize
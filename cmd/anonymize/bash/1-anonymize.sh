@@ -0,0 +1,15 @@
+go run . -rules testdata/rules.json -input testdata/input.json
+# the input is read from stdin and the salt changes hashes and jitter
+go run . -rules testdata/rules.json -salt other < testdata/input.json \
+  | jq -c '.stops[] | [.id, .location]'
+# a salt is required
+RULES=$(mktemp)
+jq 'del(.salt)' testdata/rules.json > $RULES
+go run . -rules $RULES -input testdata/input.json
+rm $RULES
+# numeric IDs are hashed and large numbers keep their precision
+RULES=$(mktemp)
+jq '.rules |= map(select(.key == "$.stops[].id"))' testdata/rules.json > $RULES
+echo '{"stops": [{"id": 9007199254740993, "quantity": 9007199254740993}]}' \
+  | go run . -rules $RULES
+rm $RULES
@@ -0,0 +1,112 @@
+// Command anonymize anonymizes a JSON document, such as the input of a
+// decision model, so it can be shared. The rules file holds the
+// [anonymize.Rules] as JSON, e.g.:
+//
+//	{
+//	  "salt": "secret",
+//	  "rules": [
+//	    {"key": "$.stops[].id", "action": "hash"},
+//	    {"key": "$.stops[].location", "action": "jitter", "distance": 100},
+//	    {"key": "$.stops[].quantity", "action": "scale", "factor": 1.5},
+//	    {"key": "$.stops[].name", "action": "drop"}
+//	  ]
+//	}
+//
+// A salt is required. The -salt flag and the ANONYMIZE_SALT env var override
+// the salt of the rules file.
+//
+// Usage:
+//
+//	go run github.com/nextmv-io/sdk/cmd/anonymize -rules rules.json \
+//	  [-input input.json] [-output output.json]
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nextmv-io/sdk/anonymize"
+)
+
+func main() {
+	rulesPath := flag.String("rules", "", "the rules file path")
+	inputPath := flag.String("input", "", "the input file path, stdin if empty")
+	outputPath := flag.String("output", "",
+		"the output file path, stdout if empty")
+	salt := flag.String("salt", "",
+		"the salt, overriding the salt of the rules file (env ANONYMIZE_SALT)")
+	flag.Parse()
+	if *rulesPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *salt == "" {
+		*salt = os.Getenv("ANONYMIZE_SALT")
+	}
+	if err := run(*rulesPath, *inputPath, *outputPath, *salt); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(rulesPath, inputPath, outputPath, salt string) (err error) {
+	var rules anonymize.Rules
+	if err := readJSON(rulesPath, &rules); err != nil {
+		return fmt.Errorf("reading rules: %w", err)
+	}
+	if salt != "" {
+		rules.Salt = salt
+	}
+	// without a salt, hashed values can be recovered by hashing guesses.
+	if rules.Salt == "" {
+		return errors.New("a salt is required, set it in the rules file, " +
+			"with -salt or with ANONYMIZE_SALT")
+	}
+	var document any
+	if err := readJSON(inputPath, &document); err != nil {
+		return fmt.Errorf("reading input: %w", err)
+	}
+	anonymized, err := anonymize.Do(document, rules)
+	if err != nil {
+		return err
+	}
+
+	var writer io.Writer = os.Stdout
+	if outputPath != "" {
+		file, err := os.Create(outputPath)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			// a failed write may only be reported when closing the file.
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		writer = file
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(anonymized)
+}
+
+// readJSON decodes the file at the path, stdin if the path is empty, into v.
+// Numbers are decoded as json.Number, so they keep their precision.
+func readJSON(path string, v any) error {
+	var reader io.Reader = os.Stdin
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		reader = file
+	}
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
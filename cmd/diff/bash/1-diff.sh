@@ -0,0 +1,9 @@
+# equal outputs print nothing
+go run . testdata/old.json testdata/old.json
+go run . testdata/old.json testdata/new.json || true
+# transient fields are ignored and small deltas tolerated
+go run . -ignore '$.statistics.run.duration,$.options.solver' -float 0.001 \
+  -tolerance '$.solutions[0].value=2' testdata/old.json testdata/new.json \
+  || true
+# newline-delimited JSON is compared line by line
+go run . testdata/old.ndjson testdata/new.ndjson || true
@@ -0,0 +1,105 @@
+// Command diff prints the differences between two JSON outputs, such as the
+// outputs of two runs. It prints the changed, added and removed keys, with the
+// delta of changed numbers. Outputs of several values, such as
+// newline-delimited JSON, are compared value by value. It exits with status 1,
+// if the outputs differ.
+//
+// Usage:
+//
+//	go run github.com/nextmv-io/sdk/cmd/diff [flags] old.json new.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/nextmv-io/sdk/golden"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	config := golden.DiffConfig{
+		Thresholds: golden.Tresholds{
+			CustomThresholds: golden.CustomThresholds{
+				Float: map[string]float64{},
+			},
+		},
+	}
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(),
+			"Usage: diff [flags] old.json new.json\n\nFlags:")
+		flags.PrintDefaults()
+	}
+	flags.Func("ignore",
+		"comma-separated keys to ignore, e.g. $.statistics.run.duration, "+
+			"may be repeated",
+		func(value string) error {
+			config.IgnoredFields = append(
+				config.IgnoredFields, strings.Split(value, ",")...,
+			)
+			return nil
+		},
+	)
+	flags.Float64Var(&config.Thresholds.Float, "float", 0,
+		"tolerance of numbers")
+	flags.Func("tolerance",
+		"tolerance of the numbers of a key as key=tolerance, may be repeated",
+		func(value string) error {
+			key, tolerance, ok := strings.Cut(value, "=")
+			if !ok {
+				return fmt.Errorf("%q is not key=tolerance", value)
+			}
+			f, err := strconv.ParseFloat(tolerance, 64)
+			if err != nil {
+				return err
+			}
+			config.Thresholds.CustomThresholds.Float[key] = f
+			return nil
+		},
+	)
+	flags.DurationVar(&config.Thresholds.Time, "time", 0,
+		"tolerance of times")
+	flags.DurationVar(&config.Thresholds.Duration, "duration", 0,
+		"tolerance of durations")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+
+	changes, err := diff(flags.Arg(0), flags.Arg(1), config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	if len(changes) > 0 {
+		return 1
+	}
+	return 0
+}
+
+func diff(
+	oldPath, newPath string, config golden.DiffConfig,
+) ([]golden.Change, error) {
+	oldData, err := os.ReadFile(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	newData, err := os.ReadFile(newPath)
+	if err != nil {
+		return nil, err
+	}
+	return golden.Diff(oldData, newData, config)
+}
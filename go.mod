@@ -12,6 +12,7 @@ require (
 	github.com/google/uuid v1.3.0
 	github.com/gorilla/schema v1.4.1
 	github.com/itzg/go-flagsfiller v1.9.1
+	github.com/klauspost/compress v1.17.11
 )
 
 require (
@@ -0,0 +1,210 @@
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/nextmv-io/sdk/flatmap"
+)
+
+// DiffConfig configures how two outputs are compared with Diff.
+type DiffConfig struct {
+	// IgnoredFields are keys that are not compared, such as transient fields
+	// like the duration of a run. The keys are [JSONPath]-like keys as
+	// produced by [flatmap.Do], e.g.: "$.statistics.run.duration". Use empty
+	// brackets ([]) to ignore a field in all elements of an array, e.g.:
+	// "$.solutions[].time".
+	//
+	// [JSONPath]: https://goessner.net/articles/JsonPath/
+	IgnoredFields []string
+	// Thresholds by data type to be used when comparing values. If the
+	// absolute difference between two values is less than or equal to the
+	// threshold, they are not reported as changed. Numbers in JSON are floats,
+	// so the Float thresholds apply to all of them.
+	Thresholds Tresholds
+}
+
+// ChangeType is the type of a change between two outputs.
+type ChangeType string
+
+const (
+	// Added means the key is only present in the new output.
+	Added ChangeType = "added"
+	// Removed means the key is only present in the old output.
+	Removed ChangeType = "removed"
+	// Changed means the value of the key differs between the outputs.
+	Changed ChangeType = "changed"
+)
+
+// Change is a difference between two outputs.
+type Change struct {
+	// Key is the [JSONPath]-like key of the value, as produced by
+	// [flatmap.Do].
+	//
+	// [JSONPath]: https://goessner.net/articles/JsonPath/
+	Key  string
+	Type ChangeType
+	// Old is the value in the old output, nil if the key was added.
+	Old any
+	// New is the value in the new output, nil if the key was removed.
+	New any
+}
+
+// Delta returns the difference between the new and the old value, if both are
+// numbers.
+func (c Change) Delta() (float64, bool) {
+	oldFloat, oldIsFloat := c.Old.(float64)
+	newFloat, newIsFloat := c.New.(float64)
+	if c.Type != Changed || !oldIsFloat || !newIsFloat {
+		return 0, false
+	}
+	return newFloat - oldFloat, true
+}
+
+// String returns the change in a human-readable form, e.g.:
+//
+//	~ $.statistics.result.value: 10 -> 8 (-2)
+//	+ $.solution.extra: true
+//	- $.solution.stops[2]: "c"
+func (c Change) String() string {
+	switch c.Type {
+	case Added:
+		return fmt.Sprintf("+ %s: %s", c.Key, diffValue(c.New))
+	case Removed:
+		return fmt.Sprintf("- %s: %s", c.Key, diffValue(c.Old))
+	}
+	s := fmt.Sprintf("~ %s: %s -> %s", c.Key, diffValue(c.Old), diffValue(c.New))
+	if delta, ok := c.Delta(); ok {
+		s += fmt.Sprintf(" (%+.6g)", delta)
+	}
+	return s
+}
+
+// diffValue returns the value as JSON.
+func diffValue(value any) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(b)
+}
+
+// Diff compares two JSON outputs and returns the changed, added and removed
+// keys, sorted by key. The outputs are flattened with [flatmap.Do] and the
+// values are compared the same way golden file tests compare them, applying
+// the thresholds of the config. Outputs can be any JSON value. If one of the
+// outputs holds several values, such as newline-delimited JSON, both are
+// compared as arrays of their values, e.g. "$[1].value" is the value field of
+// the second line.
+func Diff(oldData, newData []byte, config DiffConfig) ([]Change, error) {
+	oldValues, err := parseValues(oldData)
+	if err != nil {
+		return nil, fmt.Errorf("parsing old output: %w", err)
+	}
+	newValues, err := parseValues(newData)
+	if err != nil {
+		return nil, fmt.Errorf("parsing new output: %w", err)
+	}
+	var oldDocument, newDocument any = oldValues, newValues
+	if len(oldValues) == 1 && len(newValues) == 1 {
+		oldDocument, newDocument = oldValues[0], newValues[0]
+	}
+	flattenedOld := flatten(oldDocument)
+	flattenedNew := flatten(newDocument)
+
+	ignored := map[string]bool{}
+	for _, key := range config.IgnoredFields {
+		ignored[key] = true
+	}
+	isIgnored := func(key string) bool {
+		return ignored[key] || ignored[replaceIndicesInKeys(key)]
+	}
+	compareConfig := Config{Thresholds: config.Thresholds}
+
+	changes := []Change{}
+	for key, oldValue := range flattenedOld {
+		if isIgnored(key) {
+			continue
+		}
+		newValue, ok := flattenedNew[key]
+		if !ok {
+			changes = append(changes, Change{
+				Key: key, Type: Removed, Old: oldValue,
+			})
+			continue
+		}
+		if valuesDiffer(compareConfig, key, oldValue, newValue) {
+			changes = append(changes, Change{
+				Key: key, Type: Changed, Old: oldValue, New: newValue,
+			})
+		}
+	}
+	for key, newValue := range flattenedNew {
+		if _, ok := flattenedOld[key]; ok || isIgnored(key) {
+			continue
+		}
+		changes = append(changes, Change{Key: key, Type: Added, New: newValue})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes, nil
+}
+
+// parseValues returns the JSON values of the data, there needs to be at least
+// one.
+func parseValues(data []byte) ([]any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	values := []any{}
+	for {
+		var value any
+		err := decoder.Decode(&value)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	if len(values) == 0 {
+		return nil, errors.New("no JSON value")
+	}
+	return values, nil
+}
+
+// flatten flattens the document with [flatmap.Do]. Documents other than
+// objects are flattened as the value of a field of an object, that is removed
+// from the keys.
+func flatten(document any) map[string]any {
+	if object, ok := document.(map[string]any); ok {
+		return flatmap.Do(object)
+	}
+	flattened := map[string]any{}
+	nested := flatmap.Do(map[string]any{"document": document})
+	for key, value := range nested {
+		flattened["$"+strings.TrimPrefix(key, "$.document")] = value
+	}
+	return flattened
+}
+
+// valuesDiffer reports whether the values differ. Strings, numbers and
+// booleans are compared with valuesAreEqual, so thresholds apply. Other
+// values, such as null and empty arrays, must be deeply equal.
+func valuesDiffer(config Config, key string, oldValue, newValue any) bool {
+	switch newValue.(type) {
+	case string, float64, bool:
+		if reflect.TypeOf(oldValue) != reflect.TypeOf(newValue) {
+			return true
+		}
+		return valuesAreEqual(config, key, newValue, oldValue) != nil
+	}
+	return !reflect.DeepEqual(oldValue, newValue)
+}
@@ -0,0 +1,99 @@
+package golden
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Diff(t *testing.T) {
+	type args struct {
+		oldData string
+		newData string
+		config  DiffConfig
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "equal",
+			args: args{
+				oldData: `{"a": 1, "b": ["x"], "c": null}`,
+				newData: `{"c": null, "b": ["x"], "a": 1}`,
+			},
+			want: []string{},
+		},
+		{
+			name: "changed, added and removed",
+			args: args{
+				oldData: `{"a": 1, "b": ["x", "y"], "c": true}`,
+				newData: `{"a": 1.5, "b": ["x"], "c": "true", "d": {"e": null}}`,
+			},
+			want: []string{
+				`~ $.a: 1 -> 1.5 (+0.5)`,
+				`- $.b[1]: "y"`,
+				`~ $.c: true -> "true"`,
+				`+ $.d.e: null`,
+			},
+		},
+		{
+			name: "ignored fields",
+			args: args{
+				oldData: `{"a": 1, "b": [{"t": 1}, {"t": 2}]}`,
+				newData: `{"a": 2, "b": [{"t": 3}, {"t": 4}]}`,
+				config:  DiffConfig{IgnoredFields: []string{"$.a", "$.b[].t"}},
+			},
+			want: []string{},
+		},
+		{
+			name: "thresholds",
+			args: args{
+				oldData: `{"a": 1, "b": 1, "c": "1s"}`,
+				newData: `{"a": 1.05, "b": 2, "c": "1.5s"}`,
+				config: DiffConfig{Thresholds: Tresholds{
+					Float:    0.1,
+					Duration: 1e9,
+					CustomThresholds: CustomThresholds{
+						Float: map[string]float64{"$.b": 0.5},
+					},
+				}},
+			},
+			want: []string{`~ $.b: 1 -> 2 (+1)`},
+		},
+		{
+			name: "top-level arrays",
+			args: args{
+				oldData: `[{"a": 1}, {"a": 2}]`,
+				newData: `[{"a": 1}, {"a": 3}, 4]`,
+			},
+			want: []string{`~ $[1].a: 2 -> 3 (+1)`, `+ $[2]: 4`},
+		},
+		{
+			name: "newline-delimited JSON",
+			args: args{
+				oldData: "{\"a\": 1}\n{\"a\": 2}\n",
+				newData: "{\"a\": 1}\n",
+				config:  DiffConfig{IgnoredFields: []string{"$[].b"}},
+			},
+			want: []string{`- $[1].a: 2`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := Diff(
+				[]byte(tt.args.oldData), []byte(tt.args.newData), tt.args.config,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, change := range changes {
+				got = append(got, change.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
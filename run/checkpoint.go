@@ -0,0 +1,283 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/nextmv-io/sdk/run/decode"
+	"github.com/nextmv-io/sdk/run/storage"
+)
+
+type checkpointStateKey struct{}
+
+type restoredStateKey struct{}
+
+// SetCheckpointState sets the state of the algorithm persisted with the next
+// solution, see Checkpointer. The state is handed back by CheckpointState when
+// the run is resumed from that solution, so set it right before sending the
+// solution it belongs to. The state is taken once the runner receives the
+// solution, a state set by then for a later solution is persisted with it.
+// Without checkpoints, it does nothing.
+func SetCheckpointState(ctx context.Context, state []byte) {
+	if c, ok := ctx.Value(checkpointStateKey{}).(*checkpointer); ok {
+		c.setState(state)
+	}
+}
+
+// CheckpointState returns the state the algorithm set with
+// SetCheckpointState, if the run was resumed from a checkpoint holding one.
+// The solution of the checkpoint is the initial solution of the resumed run,
+// see InitialSolution.
+func CheckpointState(ctx context.Context) ([]byte, bool) {
+	state, ok := ctx.Value(restoredStateKey{}).([]byte)
+	return state, ok
+}
+
+// checkpoint is the progress of a run as persisted.
+type checkpoint struct {
+	// InputHash identifies the input of the run.
+	InputHash string          `json:"input_hash"`
+	Time      time.Time       `json:"time"`
+	Solution  json.RawMessage `json:"solution,omitempty"`
+	State     []byte          `json:"state,omitempty"`
+	// Complete is set, once the algorithm finished. Runs are not resumed
+	// from complete checkpoints.
+	Complete bool `json:"complete,omitempty"`
+}
+
+// checkpointer persists the latest solution and state of a run. It is safe
+// for concurrent use.
+type checkpointer struct {
+	path      string
+	inputHash string
+	interval  time.Duration
+	mutex     sync.Mutex
+	solution  any
+	state     []byte
+	// pendingState is the state set by the algorithm, it is taken with the
+	// next solution.
+	pendingState []byte
+	complete     bool
+	// dirty is set, if the checkpoint changed since the last write.
+	dirty bool
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// inputHash returns the hash of the input. The input is read and rewound, so
+// it must be an io.ReadSeeker.
+func inputHash(input any) (string, error) {
+	reader, ok := input.(io.ReadSeeker)
+	if !ok {
+		return "", errors.New("checkpoints require a seekable input")
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return "", err
+	}
+	if _, err := reader.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// readCheckpoint returns the checkpoint at the path. It returns nil, if there
+// is none.
+func readCheckpoint(ctx context.Context, path string) (*checkpoint, error) {
+	reader, err := storage.Open(ctx, path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	var c checkpoint
+	if err := json.NewDecoder(reader).Decode(&c); err != nil {
+		return nil, fmt.Errorf("reading checkpoint %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// checkpointConfig returns the configuration of the checkpoints of the
+// runner, if it enables them.
+func checkpointConfig(runnerConfig any) (Checkpointer, bool) {
+	config, ok := runnerConfig.(Checkpointer)
+	return config, ok && config.CheckpointPath() != ""
+}
+
+// resume returns the context of a run resumed from its checkpoint and the
+// checkpointer of the run. A checkpoint of another input or of a complete run
+// is ignored and overwritten.
+func (r *genericRunner[RunnerConfig, Input, Option, Solution]) resume(
+	ctx context.Context, config Checkpointer, input any,
+) (context.Context, *checkpointer, error) {
+	hash, err := inputHash(input)
+	if err != nil {
+		return ctx, nil, err
+	}
+	c := &checkpointer{
+		path:      config.CheckpointPath(),
+		interval:  config.CheckpointInterval(),
+		inputHash: hash,
+	}
+	ctx = context.WithValue(ctx, checkpointStateKey{}, c)
+
+	previous, err := readCheckpoint(ctx, c.path)
+	if err != nil || previous == nil || previous.InputHash != hash ||
+		previous.Complete {
+		return ctx, c, err
+	}
+	if len(previous.Solution) > 0 {
+		// solutions are persisted as JSON, whatever the encoder of the runner.
+		solution, err := GenericDecoder[Solution](decode.JSON())(
+			ctx, bytes.NewReader(previous.Solution),
+		)
+		if err != nil {
+			return ctx, nil, fmt.Errorf("decoding checkpoint solution: %w", err)
+		}
+		ctx = context.WithValue(ctx, initialSolutionKey{}, solution)
+	}
+	if previous.State != nil {
+		ctx = context.WithValue(ctx, restoredStateKey{}, previous.State)
+		c.state, c.pendingState = previous.State, previous.State
+	}
+	return ctx, c, nil
+}
+
+// update sets the latest solution, together with the state set before. It is
+// written right away, if the interval is 0.
+func (c *checkpointer) update(ctx context.Context, solution any) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	c.solution, c.state, c.dirty = solution, c.pendingState, true
+	c.mutex.Unlock()
+	if c.interval <= 0 {
+		c.write(ctx)
+	}
+}
+
+func (c *checkpointer) setState(state []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.pendingState = state
+}
+
+// start writes checkpoints at most once per interval, until finish is called.
+func (c *checkpointer) start(ctx context.Context) {
+	if c == nil {
+		return
+	}
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	if c.interval <= 0 {
+		// each solution is written by update.
+		close(c.done)
+		return
+	}
+	go func() {
+		defer close(c.done)
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				c.write(ctx)
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+}
+
+// finish stops writing checkpoints and writes the final one. It is marked
+// complete, if the algorithm finished.
+func (c *checkpointer) finish(ctx context.Context, complete bool) {
+	if c == nil {
+		return
+	}
+	close(c.stop)
+	<-c.done
+	if complete {
+		c.mutex.Lock()
+		c.complete, c.dirty = true, true
+		c.mutex.Unlock()
+	}
+	c.write(ctx)
+}
+
+// write persists the latest solution and state, if they changed. Failures are
+// logged, so they do not end the run. Checkpoints are written even if the run
+// was cancelled.
+func (c *checkpointer) write(ctx context.Context) {
+	ctx = context.WithoutCancel(ctx)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !c.dirty {
+		return
+	}
+	if err := c.persist(ctx); err != nil {
+		log.Printf("writing checkpoint %s: %v", c.path, err)
+		return
+	}
+	c.dirty = false
+}
+
+func (c *checkpointer) persist(ctx context.Context) error {
+	data := checkpoint{
+		InputHash: c.inputHash,
+		Time:      time.Now(),
+		State:     c.state,
+		Complete:  c.complete,
+	}
+	if c.solution != nil {
+		solution, err := json.Marshal(c.solution)
+		if err != nil {
+			return err
+		}
+		data.Solution = solution
+	}
+	// local checkpoints are replaced atomically, so a run stopped while
+	// writing leaves the previous checkpoint intact.
+	path, local := storage.LocalPath(c.path)
+	if !local {
+		return writeCheckpoint(ctx, c.path, data)
+	}
+	if err := writeCheckpoint(ctx, path+".tmp", data); err != nil {
+		return errors.Join(err, removeIfExists(path+".tmp"))
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// writeCheckpoint writes the checkpoint to the URI.
+func writeCheckpoint(ctx context.Context, uri string, data checkpoint) error {
+	writer, err := storage.Create(ctx, uri)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(writer).Encode(data); err != nil {
+		return errors.Join(err, closeWriter(writer, err))
+	}
+	return writer.Close()
+}
+
+// removeIfExists removes the file at the path, if there is one.
+func removeIfExists(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
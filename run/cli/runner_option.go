@@ -7,15 +7,18 @@ import (
 	"github.com/nextmv-io/sdk/run/encode"
 )
 
-// Decode sets the decoder of a CLIRunner.
+// Decode sets the decoder of the input and initial solution of a CLIRunner.
 func Decode[Input, Option, Solution any, Decoder decode.Decoder](
 	d Decoder,
 ) func(
 	run.Runner[run.CLIRunnerConfig, Input, Option, Solution],
 ) {
-	return run.InputDecode[run.CLIRunnerConfig, Input, Option, Solution](
-		run.GenericDecoder[Input](d),
-	)
+	return func(r run.Runner[run.CLIRunnerConfig, Input, Option, Solution]) {
+		r.SetInputDecoder(run.GenericDecoder[Input](d))
+		run.SolutionDecode[run.CLIRunnerConfig, Input, Option](
+			run.GenericDecoder[Solution](d),
+		)(r)
+	}
 }
 
 // Encode sets the encoder of a CLIRunner.
@@ -4,31 +4,71 @@ import (
 	"context"
 	"io"
 	"os"
+
+	"github.com/nextmv-io/sdk/run/storage"
 )
 
 // CliIOProducer is the IOProducer for the CliRunner. The input and output paths
-// are used to configure the input and output readers and writers. If the paths
-// are empty, os.Stdin and os.Stdout are used.
-func CliIOProducer(_ context.Context, cfg CLIRunnerConfig) (IOData, error) {
-	reader := os.Stdin
+// are used to configure the input and output readers and writers. Paths are
+// URIs resolved with the storage package, so they can point at remote objects,
+// e.g. s3://bucket/input.json. If the paths are empty, os.Stdin and os.Stdout
+// are used. If an initial solution path is configured, the file is used to
+// warm-start the algorithm. With the Split output format, the output path is
+// the directory the encoder writes to.
+func CliIOProducer(
+	ctx context.Context, cfg CLIRunnerConfig,
+) (_ IOData, err error) {
+	// release the objects opened so far, if a later one fails to open or the
+	// input cannot be read. The output is discarded, see storage.Aborter.
+	var opened []io.Closer
+	defer func() {
+		if err != nil {
+			for _, closer := range opened {
+				_ = closeWriter(closer, err)
+			}
+		}
+	}()
+	var reader io.Reader = os.Stdin
 	if cfg.Runner.Input.Path != "" {
-		r, err := os.Open(cfg.Runner.Input.Path)
+		r, err := storage.Open(ctx, cfg.Runner.Input.Path)
 		if err != nil {
 			return ioData{}, err
 		}
+		opened = append(opened, r)
 		reader = r
 	}
+	options := inputLimits(cfg)
+	if cfg.Runner.Input.InitialSolution != "" {
+		initialSolution, err := storage.Open(
+			ctx, cfg.Runner.Input.InitialSolution,
+		)
+		if err != nil {
+			return ioData{}, err
+		}
+		opened = append(opened, initialSolution)
+		options = append(options, WithInitialSolution(initialSolution))
+	}
+	format, err := cfg.OutputFormat()
+	if err != nil {
+		return ioData{}, err
+	}
 	var writer io.Writer = os.Stdout
-	if cfg.Runner.Output.Path != "" {
-		w, err := os.Create(cfg.Runner.Output.Path)
+	switch {
+	case format == Split:
+		// the encoder writes the files into the output directory.
+		writer = nil
+	case cfg.Runner.Output.Path != "":
+		w, err := storage.Create(ctx, cfg.Runner.Output.Path)
 		if err != nil {
 			return ioData{}, err
 		}
+		opened = append(opened, w)
 		writer = w
 	}
 	return NewIOData(
 		reader,
 		nil,
 		writer,
+		options...,
 	)
 }
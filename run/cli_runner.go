@@ -1,6 +1,8 @@
 package run
 
 import (
+	"runtime/debug"
+
 	"github.com/nextmv-io/sdk/run/decode"
 	"github.com/nextmv-io/sdk/run/encode"
 	"github.com/nextmv-io/sdk/run/validate"
@@ -27,5 +29,11 @@ func NewCLIRunner[Input, Option, Solution any](
 		option(runner)
 	}
 
+	// The CLI runner owns the process, so the garbage collector can work
+	// towards the memory limit, too.
+	if limit := runner.RunnerConfig().Runner.Limits.Memory; limit > 0 {
+		debug.SetMemoryLimit(limit)
+	}
+
 	return runner
 }
@@ -1,6 +1,9 @@
 package run
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 // CPUProfiler is the interface a runner configuration can implement to return
 // the CPU profile path.
@@ -14,6 +17,63 @@ type MemoryProfiler interface {
 	MemoryProfilePath() string
 }
 
+// TraceProfiler is the interface a runner configuration can implement to
+// return the execution trace path.
+type TraceProfiler interface {
+	TraceProfilePath() string
+}
+
+// BlockProfiler is the interface a runner configuration can implement to
+// return the block profile path.
+type BlockProfiler interface {
+	BlockProfilePath() string
+}
+
+// MutexProfiler is the interface a runner configuration can implement to
+// return the mutex profile path.
+type MutexProfiler interface {
+	MutexProfilePath() string
+}
+
+// LeakChecker is the interface a runner configuration can implement to
+// control whether goroutines leaked by the algorithm are reported.
+type LeakChecker interface {
+	CheckLeaks() bool
+}
+
+// ResourceLimiter is the interface a runner configuration can implement to
+// limit the resources of a run. A value of 0 means no limit. The memory limit
+// is compared to the live heap of the whole process, which parallel runs of
+// the HTTPRunner or the WorkerRunner share, so exceeding it terminates all of
+// them.
+type ResourceLimiter interface {
+	MemoryLimit() int64
+	MaxSolutions() int
+}
+
+// InputLimiter is the interface a runner configuration can implement to limit
+// the size of the input, as received and after decompressing it. A value of 0
+// means no limit.
+type InputLimiter interface {
+	InputLimits() (size, decompressed int64)
+}
+
+// inputLimits returns the IODataOptions applying the input limits of the
+// runner configuration, if it implements InputLimiter.
+func inputLimits(runnerConfig any) []IODataOption {
+	limiter, ok := runnerConfig.(InputLimiter)
+	if !ok {
+		return nil
+	}
+	return []IODataOption{WithInputLimits(limiter.InputLimits())}
+}
+
+// TracingPather is the interface a runner configuration can implement to
+// return the path of the file spans are written to.
+type TracingPather interface {
+	TracingPath() string
+}
+
 // OutputPather is the interface a runner configuration can implement to return
 // the output path.
 type OutputPather interface {
@@ -26,23 +86,98 @@ type SolutionLimiter interface {
 	Solutions() (Solutions, error)
 }
 
+// OutputFormatter is the interface a runner configuration can implement to
+// control how the solutions are laid out in the output.
+type OutputFormatter interface {
+	OutputFormat() (OutputFormat, error)
+}
+
+// OutputValidator is the interface a runner configuration can implement to
+// validate the solutions before they are encoded. An empty schema path means
+// the schema is generated from the solution type.
+type OutputValidator interface {
+	OutputValidation() (Validation, error)
+	OutputSchemaPath() string
+}
+
+// Checkpointer is the interface a runner configuration can implement to
+// persist the progress of a run, so it can be resumed after a restart. The
+// checkpoint is written to the path at most once per interval, an interval of
+// 0 writes each solution. Once the algorithm finishes, the checkpoint is marked
+// complete and the next run of the input starts over. Solutions are persisted
+// as JSON, whatever the encoder of the runner. An empty path disables
+// checkpoints.
+type Checkpointer interface {
+	CheckpointPath() string
+	CheckpointInterval() time.Duration
+}
+
+// VersionPrinter is the interface a runner configuration can implement to
+// print the build information of the application instead of running, see
+// schema.ReadBuildInfo.
+type VersionPrinter interface {
+	PrintVersion() bool
+}
+
 // CLIRunnerConfig is the configuration of the  CliRunner.
 type CLIRunnerConfig struct {
 	Runner struct {
-		Input struct {
-			Path string `usage:"The input file path"`
+		Seed    int64 `usage:"The seed of the random number generators of a run, generated if 0"`
+		Version bool  `usage:"Print the build information as JSON and exit"`
+		Input   struct {
+			Path            string `usage:"The input file path"`
+			InitialSolution string `flag:"runner.input.initial_solution" usage:"The file path of a solution to warm-start the algorithm with"`
 		}
 		Profile struct {
 			CPU    string `usage:"The CPU profile file path"`
 			Memory string `usage:"The memory profile file path"`
+			Trace  string `usage:"The execution trace file path"`
+			Block  string `usage:"The block profile file path"`
+			Mutex  string `usage:"The mutex profile file path"`
+			Leaks  bool   `usage:"Report goroutines the algorithm leaves running"`
 		}
 		Output struct {
-			Path      string `usage:"The output file path"`
-			Solutions string `default:"last" usage:"{all, last}"`
+			Path       string `usage:"The output file path"`
+			Solutions  string `default:"last" usage:"{all, last}"`
+			Format     string `default:"concatenated" usage:"{concatenated, array, ndjson, split}, split writes one file per solution and a manifest into the output path as directory"`
+			Validation string `default:"off" usage:"{off, warn, fail}, whether solutions are validated against the output schema and invalid ones are logged or fail the run"`
+			Schema     string `usage:"The file path of the JSON schema solutions are validated against, generated from the solution type if empty"`
+		}
+		Tracing struct {
+			Path string `usage:"The file path spans are written to as JSON lines"`
+		}
+		Limits struct {
+			Memory    int64 `usage:"The soft memory limit in bytes, exceeding it terminates the run (0 means no limit)"`
+			Solutions int   `usage:"The max number of solutions accepted from the algorithm (0 means no limit)"`
+			Input     struct {
+				Size         int64 `usage:"The max size of the input in bytes, as received (0 means no limit)"`
+				Decompressed int64 `usage:"The max size of the input in bytes, after decompressing it (0 means no limit)"`
+			}
+		}
+		Checkpoint struct {
+			Path     string        `usage:"The path the latest solution and state are persisted to, a run of the same input resumes from it"`
+			Interval time.Duration `default:"1m" usage:"The min duration between checkpoints (0 writes each solution)"`
 		}
 	}
 }
 
+// MemoryLimit returns the soft memory limit in bytes.
+func (c CLIRunnerConfig) MemoryLimit() int64 {
+	return c.Runner.Limits.Memory
+}
+
+// MaxSolutions returns the max number of solutions accepted from the
+// algorithm.
+func (c CLIRunnerConfig) MaxSolutions() int {
+	return c.Runner.Limits.Solutions
+}
+
+// InputLimits returns the max size of the input in bytes, as received and
+// after decompressing it.
+func (c CLIRunnerConfig) InputLimits() (size, decompressed int64) {
+	return c.Runner.Limits.Input.Size, c.Runner.Limits.Input.Decompressed
+}
+
 // OutputPath returns the output path.
 func (c CLIRunnerConfig) OutputPath() string {
 	return c.Runner.Output.Path
@@ -58,11 +193,73 @@ func (c CLIRunnerConfig) MemoryProfilePath() string {
 	return c.Runner.Profile.Memory
 }
 
+// TraceProfilePath returns the execution trace path.
+func (c CLIRunnerConfig) TraceProfilePath() string {
+	return c.Runner.Profile.Trace
+}
+
+// BlockProfilePath returns the block profile path.
+func (c CLIRunnerConfig) BlockProfilePath() string {
+	return c.Runner.Profile.Block
+}
+
+// MutexProfilePath returns the mutex profile path.
+func (c CLIRunnerConfig) MutexProfilePath() string {
+	return c.Runner.Profile.Mutex
+}
+
+// CheckLeaks returns whether leaked goroutines are reported.
+func (c CLIRunnerConfig) CheckLeaks() bool {
+	return c.Runner.Profile.Leaks
+}
+
+// TracingPath returns the tracing path.
+func (c CLIRunnerConfig) TracingPath() string {
+	return c.Runner.Tracing.Path
+}
+
 // Solutions returns the configured solutions.
 func (c CLIRunnerConfig) Solutions() (Solutions, error) {
 	return ParseSolutions(c.Runner.Output.Solutions)
 }
 
+// Seed returns the seed of the random number generators of a run.
+func (c CLIRunnerConfig) Seed() int64 {
+	return c.Runner.Seed
+}
+
+// PrintVersion returns whether to print the build information instead of
+// running.
+func (c CLIRunnerConfig) PrintVersion() bool {
+	return c.Runner.Version
+}
+
+// CheckpointPath returns the path of the checkpoint.
+func (c CLIRunnerConfig) CheckpointPath() string {
+	return c.Runner.Checkpoint.Path
+}
+
+// CheckpointInterval returns the min duration between checkpoints.
+func (c CLIRunnerConfig) CheckpointInterval() time.Duration {
+	return c.Runner.Checkpoint.Interval
+}
+
+// OutputFormat returns the configured output format.
+func (c CLIRunnerConfig) OutputFormat() (OutputFormat, error) {
+	return ParseOutputFormat(c.Runner.Output.Format)
+}
+
+// OutputValidation returns how solutions are validated.
+func (c CLIRunnerConfig) OutputValidation() (Validation, error) {
+	return ParseValidation(c.Runner.Output.Validation)
+}
+
+// OutputSchemaPath returns the path of the schema solutions are validated
+// against.
+func (c CLIRunnerConfig) OutputSchemaPath() string {
+	return c.Runner.Output.Schema
+}
+
 // Solutions can be all or last.
 type Solutions int
 
@@ -92,3 +289,93 @@ func ParseSolutions(s string) (Solutions, error) {
 		return Last, errors.New(`solutions must be "all" or "last"`)
 	}
 }
+
+// OutputFormat is the layout of the solutions in the output.
+type OutputFormat int
+
+// Constants for the layout of the solutions in the output.
+const (
+	// Concatenated writes the encoded solutions one after the other.
+	Concatenated OutputFormat = iota
+	// Array writes the solutions as a JSON array.
+	Array
+	// NDJSON writes each solution as JSON on a line of its own.
+	NDJSON
+	// Split writes each solution to a file of its own, solution-0001.json,
+	// ..., in the output directory, and a manifest.json listing them.
+	Split
+)
+
+func (f OutputFormat) String() string {
+	switch f {
+	case Array:
+		return "array"
+	case NDJSON:
+		return "ndjson"
+	case Split:
+		return "split"
+	default:
+		return "concatenated"
+	}
+}
+
+// ParseOutputFormat converts "concatenated", "array", "ndjson" and "split" to
+// the corresponding OutputFormat.
+func ParseOutputFormat(s string) (OutputFormat, error) {
+	switch s {
+	case "", "concatenated":
+		return Concatenated, nil
+	case "array":
+		return Array, nil
+	case "ndjson":
+		return NDJSON, nil
+	case "split":
+		return Split, nil
+	default:
+		return Concatenated, errors.New(
+			`output format must be "concatenated", "array", "ndjson" or "split"`,
+		)
+	}
+}
+
+// Validation is how solutions are validated before they are encoded.
+type Validation int
+
+// Constants for the validation of solutions.
+const (
+	// ValidationOff does not validate solutions.
+	ValidationOff Validation = iota
+	// ValidationWarn logs invalid solutions and encodes them anyway.
+	ValidationWarn
+	// ValidationFail fails the run on the first invalid solution, it is not
+	// encoded.
+	ValidationFail
+)
+
+func (v Validation) String() string {
+	switch v {
+	case ValidationWarn:
+		return "warn"
+	case ValidationFail:
+		return "fail"
+	default:
+		return "off"
+	}
+}
+
+// ParseValidation converts "off", "warn" and "fail" to the corresponding
+// Validation.
+func ParseValidation(s string) (Validation, error) {
+	switch s {
+	case "", "off":
+		return ValidationOff, nil
+	case "warn":
+		return ValidationWarn, nil
+	case "fail":
+		return ValidationFail, nil
+	default:
+		return ValidationOff, errors.New(
+			`output validation must be "off", "warn" or "fail"`,
+		)
+	}
+}
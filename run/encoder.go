@@ -7,7 +7,9 @@ import (
 	"io"
 	"strings"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/nextmv-io/sdk/run/encode"
+	"github.com/nextmv-io/sdk/run/storage"
 )
 
 // GenericEncoder returns a new Encoder that encodes the solution using the
@@ -24,35 +26,27 @@ type genericEncoder[Solution, Options any] struct {
 }
 
 // Encode encodes the solution using the given encoder. If a given output path
-// ends in .gz, it will be gzipped after encoding. The writer needs to be an
-// io.Writer.
+// ends in .gz or .zst, it will be gzipped or zstd compressed while encoding.
+// The writer needs to be an io.Writer.
 func (g *genericEncoder[Solution, Options]) Encode(
-	_ context.Context,
+	ctx context.Context,
 	solutions <-chan Solution,
 	writer any,
 	runnerCfg any,
 	_ Options,
 ) (err error) {
-	closer, ok := writer.(io.Closer)
-	if ok {
-		defer func() {
-			tempErr := closer.Close()
-			// the first error is the most important
-			if err == nil {
-				err = tempErr
-			}
-		}()
-	}
-
-	ioWriter, ok := writer.(io.Writer)
-	if !ok {
-		err = errors.New("encoder is not compatible with configured IOProducer")
-		return err
-	}
+	defer func() {
+		tempErr := closeWriter(writer, err)
+		// the first error is the most important
+		if err == nil {
+			err = tempErr
+		}
+	}()
 
-	if outputPather, ok := runnerCfg.(OutputPather); ok {
-		if strings.HasSuffix(outputPather.OutputPath(), ".gz") {
-			ioWriter = gzip.NewWriter(ioWriter)
+	format := Concatenated
+	if formatter, ok := runnerCfg.(OutputFormatter); ok {
+		if format, err = formatter.OutputFormat(); err != nil {
+			return err
 		}
 	}
 
@@ -69,16 +63,53 @@ func (g *genericEncoder[Solution, Options]) Encode(
 				last = solution
 				lastIsSet = true
 			}
-			if !lastIsSet {
-				return nil
-			}
 			tempSolutions := make(chan Solution, 1)
-			tempSolutions <- last
+			if lastIsSet {
+				tempSolutions <- last
+			}
 			close(tempSolutions)
 			solutions = tempSolutions
 		}
 	}
 
+	if format == Split {
+		outputPather, ok := runnerCfg.(OutputPather)
+		if !ok || outputPather.OutputPath() == "" {
+			return errors.New("split output requires an output path")
+		}
+		return g.encodeSplit(ctx, outputPather.OutputPath(), solutions)
+	}
+
+	ioWriter, ok := writer.(io.Writer)
+	if !ok {
+		err = errors.New("encoder is not compatible with configured IOProducer")
+		return err
+	}
+
+	if outputPather, ok := runnerCfg.(OutputPather); ok {
+		cw, retErr := compressor(ioWriter, outputPather.OutputPath())
+		if retErr != nil {
+			return retErr
+		}
+		if cw != nil {
+			// closing the compressor writes the end of the stream, it needs
+			// to happen before the writer is closed.
+			defer func() {
+				tempErr := cw.Close()
+				if err == nil {
+					err = tempErr
+				}
+			}()
+			ioWriter = cw
+		}
+	}
+
+	switch format {
+	case Array:
+		return g.encodeArray(ioWriter, solutions)
+	case NDJSON:
+		return g.encodeNDJSON(ioWriter, solutions)
+	}
 	for solution := range solutions {
 		err := g.encoder.Encode(ioWriter, solution)
 		if err != nil {
@@ -88,6 +119,31 @@ func (g *genericEncoder[Solution, Options]) Encode(
 	return nil
 }
 
+// closeWriter closes the writer, if it is an io.Closer. If the output failed
+// with err, writers implementing storage.Aborter discard the output instead.
+func closeWriter(writer any, err error) error {
+	if aborter, ok := writer.(storage.Aborter); ok && err != nil {
+		return aborter.CloseWithError(err)
+	}
+	if closer, ok := writer.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// compressor returns a writer compressing the data written to the given writer
+// according to the extension of the path. It returns nil, if the path has no
+// extension of a supported compression.
+func compressor(writer io.Writer, path string) (io.WriteCloser, error) {
+	switch {
+	case strings.HasSuffix(path, ".gz"):
+		return gzip.NewWriter(writer), nil
+	case strings.HasSuffix(path, ".zst"):
+		return zstd.NewWriter(writer)
+	}
+	return nil, nil
+}
+
 func (g *genericEncoder[Solution, Options]) ContentType() string {
 	contentTyper, ok := g.encoder.(ContentTyper)
 	if !ok {
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"reflect"
 	"strings"
 
 	"github.com/itzg/go-flagsfiller"
@@ -14,15 +15,7 @@ func FlagParser[Option, RunnerCfg any]() (
 	runnerConfig RunnerCfg, option Option, err error,
 ) {
 	// create a FlagSetFiller
-	filler := flagsfiller.New(
-		flagsfiller.WithEnv(""),
-		flagsfiller.WithFieldRenamer(
-			func(name string) string {
-				repl := strings.ReplaceAll(name, "-", ".")
-				return strings.ToLower(repl)
-			},
-		),
-	)
+	filler := newFlagSetFiller()
 	err = filler.Fill(flag.CommandLine, &option)
 	if err != nil {
 		return runnerConfig, option, err
@@ -38,6 +31,142 @@ func FlagParser[Option, RunnerCfg any]() (
 	return runnerConfig, option, nil
 }
 
+// ParseFlags parses the given arguments and environment, e.g. os.Args[1:] and
+// os.Environ(), and returns a runner config and options. Unlike FlagParser, it
+// uses a flag set of its own, so it leaves the global flags untouched and can
+// be called repeatedly. Environment variables take precedence over defaults
+// and arguments over environment variables.
+func ParseFlags[Option, RunnerCfg any](args, env []string) (
+	runnerConfig RunnerCfg, option Option, err error,
+) {
+	return parseFlags[Option, RunnerCfg](args, env, envRenamer)
+}
+
+// parseFlags is ParseFlags naming the env vars of the fields with the given
+// renamer.
+func parseFlags[Option, RunnerCfg any](
+	args, env []string, rename flagsfiller.Renamer,
+) (runnerConfig RunnerCfg, option Option, err error) {
+	flagSet := flag.NewFlagSet("runner", flag.ContinueOnError)
+	filler := newFlagSetFiller(flagsfiller.NoSetFromEnv())
+	if err := filler.Fill(flagSet, &option); err != nil {
+		return runnerConfig, option, err
+	}
+	if err := filler.Fill(flagSet, &runnerConfig); err != nil {
+		return runnerConfig, option, err
+	}
+
+	names := envNames(&option, rename)
+	for flagName, envName := range envNames(&runnerConfig, rename) {
+		names[flagName] = envName
+	}
+	values := map[string]string{}
+	for _, variable := range env {
+		if name, value, ok := strings.Cut(variable, "="); ok {
+			values[name] = value
+		}
+	}
+	flagSet.VisitAll(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+		envName, ok := names[f.Name]
+		if !ok {
+			return
+		}
+		if value, ok := values[envName]; ok {
+			if setErr := f.Value.Set(value); setErr != nil {
+				err = fmt.Errorf(
+					"failed to set from environment variable %s: %w",
+					envName, setErr,
+				)
+			}
+		}
+	})
+	if err != nil {
+		return runnerConfig, option, err
+	}
+
+	flagSet.Usage = func() {
+		fmt.Fprint(
+			flagSet.Output(),
+			"Nextmv Hybrid Optimization Platform\nUsage:\n",
+		)
+		flagSet.PrintDefaults()
+	}
+	err = flagSet.Parse(args)
+	return runnerConfig, option, err
+}
+
+// envRenamer and flagRenamer turn the names of fields, e.g. Runner-Input-Path
+// for the Path field nested in the Input and Runner fields, into the names of
+// their env vars and flags.
+var (
+	envRenamer = flagsfiller.CompositeRenamer(
+		flagsfiller.PrefixRenamer(""), flagsfiller.ScreamingSnakeRenamer(),
+	)
+	flagRenamer = func(name string) string {
+		repl := strings.ReplaceAll(name, "-", ".")
+		return strings.ToLower(repl)
+	}
+)
+
+func newFlagSetFiller(
+	options ...flagsfiller.FillerOption,
+) *flagsfiller.FlagSetFiller {
+	return flagsfiller.New(append([]flagsfiller.FillerOption{
+		flagsfiller.WithEnvRenamer(envRenamer),
+		flagsfiller.WithFieldRenamer(flagRenamer),
+	}, options...)...)
+}
+
+// envNames returns the names of the env vars of the flags the filler creates
+// for the fields of the struct v points to, by flag name. The fields are
+// walked as the filler does and named with the given renamer.
+func envNames(v any, rename flagsfiller.Renamer) map[string]string {
+	names := map[string]string{}
+	walkEnvNames(names, "", reflect.TypeOf(v).Elem(), rename)
+	return names
+}
+
+func walkEnvNames(
+	names map[string]string,
+	prefix string,
+	t reflect.Type,
+	rename flagsfiller.Renamer,
+) {
+	if prefix != "" {
+		prefix += "-"
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		flagName, hasFlagName := field.Tag.Lookup("flag")
+		if hasFlagName && flagName == "" {
+			continue
+		}
+		switch {
+		case field.Type.Kind() == reflect.Struct:
+			walkEnvNames(names, prefix+field.Name, field.Type, rename)
+		case field.Type.Kind() == reflect.Ptr &&
+			field.Type.Elem().Kind() == reflect.Struct:
+			// the filler does not prefix the fields of pointed to structs.
+			walkEnvNames(names, field.Name, field.Type.Elem(), rename)
+		case field.IsExported():
+			name := prefix + field.Name
+			if !hasFlagName {
+				flagName = flagRenamer(name)
+			}
+			envName, ok := field.Tag.Lookup("env")
+			if !ok {
+				envName = rename(name)
+			}
+			if envName != "" {
+				names[flagName] = envName
+			}
+		}
+	}
+}
+
 func usage() {
 	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 	out := fs.Output()
@@ -2,13 +2,19 @@ package run
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log"
 	"os"
 	"reflect"
 	"runtime"
-	"runtime/pprof"
 	"sync"
 	"time"
+
+	"github.com/nextmv-io/sdk/run/decode"
+	"github.com/nextmv-io/sdk/run/storage"
+	"github.com/nextmv-io/sdk/run/trace"
+	"github.com/nextmv-io/sdk/run/validate"
 )
 
 type start string
@@ -20,6 +26,15 @@ const Start start = "start"
 // Data is the key for additional data of the run.
 const Data data = "data"
 
+type initialSolutionKey struct{}
+
+// InitialSolution returns the solution the algorithm is warm-started with, if
+// the run was given one.
+func InitialSolution[Solution any](ctx context.Context) (Solution, bool) {
+	solution, ok := ctx.Value(initialSolutionKey{}).(Solution)
+	return solution, ok
+}
+
 // GenericRunner creates a new runner from the given components.
 func GenericRunner[RunnerConfig, Input, Option, Solution any](
 	ioHandler IOProducer[RunnerConfig],
@@ -35,27 +50,155 @@ func GenericRunner[RunnerConfig, Input, Option, Solution any](
 	if err != nil {
 		log.Fatal(err)
 	}
+	runner, err := newGenericRunner(
+		runnerConfig, option, ioHandler, inputDecoder, inputValidator,
+		optionDecoder, handler, encoder,
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return runner
+}
+
+// GenericRunnerFromArgs creates a new runner from the given components, like
+// GenericRunner. The configuration is parsed from the given arguments and
+// environment with ParseFlags instead of the command line, so several runners
+// can be created in one process, e.g. in tests. Errors are returned instead of
+// exiting the process.
+func GenericRunnerFromArgs[RunnerConfig, Input, Option, Solution any](
+	args []string,
+	env []string,
+	ioHandler IOProducer[RunnerConfig],
+	inputDecoder Decoder[Input],
+	inputValidator Validator[Input],
+	optionDecoder Decoder[Option],
+	handler Algorithm[Input, Option, Solution],
+	encoder Encoder[Solution, Option],
+) (Runner[RunnerConfig, Input, Option, Solution], error) {
+	runnerConfig, option, err := ParseFlags[Option, RunnerConfig](args, env)
+	if err != nil {
+		return nil, err
+	}
+	runner, err := newGenericRunner(
+		runnerConfig, option, ioHandler, inputDecoder, inputValidator,
+		optionDecoder, handler, encoder,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return runner, nil
+}
+
+func newGenericRunner[RunnerConfig, Input, Option, Solution any](
+	runnerConfig RunnerConfig,
+	option Option,
+	ioHandler IOProducer[RunnerConfig],
+	inputDecoder Decoder[Input],
+	inputValidator Validator[Input],
+	optionDecoder Decoder[Option],
+	handler Algorithm[Input, Option, Solution],
+	encoder Encoder[Solution, Option],
+) (*genericRunner[RunnerConfig, Input, Option, Solution], error) {
+	tracer, err := newTracer(runnerConfig)
+	if err != nil {
+		return nil, err
+	}
+	validation, solutionValidator, err := newSolutionValidator[Solution](
+		runnerConfig,
+	)
+	if err != nil {
+		return nil, err
+	}
 	return &genericRunner[RunnerConfig, Input, Option, Solution]{
-		IOProducer:       ioHandler,
-		InputDecoder:     inputDecoder,
-		InputValidator:   inputValidator,
-		OptionDecoder:    optionDecoder,
-		Algorithm:        handler,
-		Encoder:          encoder,
-		runnerConfig:     runnerConfig,
-		flagParsedOption: option,
+		IOProducer:         ioHandler,
+		InputDecoder:       inputDecoder,
+		InputValidator:     inputValidator,
+		OptionDecoder:      optionDecoder,
+		SolutionDecoder:    GenericDecoder[Solution](decode.JSON()),
+		SolutionValidator:  solutionValidator,
+		Algorithm:          handler,
+		Encoder:            encoder,
+		runnerConfig:       runnerConfig,
+		flagParsedOption:   option,
+		tracer:             tracer,
+		solutionValidation: validation,
+	}, nil
+}
+
+// newSolutionValidator returns how solutions are validated and, if they are,
+// the validator of the schema configured in the runner configuration.
+func newSolutionValidator[Solution any](
+	runnerConfig any,
+) (Validation, Validator[Solution], error) {
+	config, ok := runnerConfig.(OutputValidator)
+	if !ok {
+		return ValidationOff, nil, nil
+	}
+	validation, err := config.OutputValidation()
+	if err != nil || validation == ValidationOff {
+		return ValidationOff, nil, err
+	}
+	var schema []byte
+	if path := config.OutputSchemaPath(); path != "" {
+		if schema, err = os.ReadFile(path); err != nil {
+			return ValidationOff, nil, err
+		}
+	}
+	validator, err := validate.OutputJSON[Solution](schema)
+	if err != nil {
+		return ValidationOff, nil, err
+	}
+	return validation, validator, nil
+}
+
+// newTracer returns a tracer writing to the tracing path of the runner
+// configuration, if one is configured. Otherwise nothing is traced.
+func newTracer(runnerConfig any) (trace.Tracer, error) {
+	if tracingPather, ok := runnerConfig.(TracingPather); ok &&
+		tracingPather.TracingPath() != "" {
+		return trace.File(tracingPather.TracingPath())
 	}
+	return trace.Noop(), nil
 }
 
 type genericRunner[RunnerConfig, Input, Option, Solution any] struct {
-	IOProducer       IOProducer[RunnerConfig]
-	InputDecoder     Decoder[Input]
-	InputValidator   Validator[Input]
-	OptionDecoder    Decoder[Option]
-	Algorithm        Algorithm[Input, Option, Solution]
-	Encoder          Encoder[Solution, Option]
-	runnerConfig     RunnerConfig
-	flagParsedOption Option
+	IOProducer         IOProducer[RunnerConfig]
+	InputDecoder       Decoder[Input]
+	InputValidator     Validator[Input]
+	OptionDecoder      Decoder[Option]
+	SolutionDecoder    Decoder[Solution]
+	SolutionValidator  Validator[Solution]
+	Algorithm          Algorithm[Input, Option, Solution]
+	Encoder            Encoder[Solution, Option]
+	runnerConfig       RunnerConfig
+	flagParsedOption   Option
+	tracer             trace.Tracer
+	solutionValidation Validation
+	// shared is set on copies of the runner, which share its tracer, so they
+	// do not close it, see cloneRunner.
+	shared bool
+}
+
+// tracerCloser is implemented by runners closing their tracer, see trace.File.
+type tracerCloser interface {
+	closeTracer() error
+}
+
+// closeTracer closes the tracer of the runner, if it can be closed.
+func closeTracer(runner any) error {
+	if closer, ok := runner.(tracerCloser); ok {
+		return closer.closeTracer()
+	}
+	return nil
+}
+
+func (r *genericRunner[
+	RunnerConfig, Input, Option, Solution,
+]) closeTracer() error {
+	if closer, ok := r.tracer.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
 }
 
 func (r *genericRunner[RunnerConfig, Input, Option, Solution]) handleCPUProfile(
@@ -67,21 +210,56 @@ func (r *genericRunner[RunnerConfig, Input, Option, Solution]) handleCPUProfile(
 	if cpuProfiler, ok := runnerConfig.(CPUProfiler); ok &&
 		cpuProfiler.CPUProfilePath() != "" {
 		// CPU profiler.
-		f, err := os.Create(cpuProfiler.CPUProfilePath())
-		if err != nil {
-			return deferFunc, err
-		}
-		deferFunc = func() error {
-			return f.Close()
-		}
+		return startCPUProfile(cpuProfiler.CPUProfilePath())
+	}
+	return deferFunc, nil
+}
 
-		if err := pprof.StartCPUProfile(f); err != nil {
-			return deferFunc, err
-		}
-		deferFunc = func() error {
-			pprof.StopCPUProfile()
-			return f.Close()
-		}
+func (r *genericRunner[RunnerConfig, Input, Option, Solution],
+) handleTraceProfile(runnerConfig any,
+) (deferFunc func() error, err error) {
+	deferFunc = func() error {
+		return nil
+	}
+	if traceProfiler, ok := runnerConfig.(TraceProfiler); ok &&
+		traceProfiler.TraceProfilePath() != "" {
+		// Execution tracer.
+		return startTrace(traceProfiler.TraceProfilePath())
+	}
+	return deferFunc, nil
+}
+
+func (r *genericRunner[RunnerConfig, Input, Option, Solution],
+) handleBlockProfile(runnerConfig any,
+) (deferFunc func() error, err error) {
+	deferFunc = func() error {
+		return nil
+	}
+	if blockProfiler, ok := runnerConfig.(BlockProfiler); ok &&
+		blockProfiler.BlockProfilePath() != "" {
+		// Record every blocking event.
+		return startSampledProfile("block", blockProfiler.BlockProfilePath(),
+			func(rate int) int {
+				runtime.SetBlockProfileRate(rate)
+				return 0
+			},
+		)
+	}
+	return deferFunc, nil
+}
+
+func (r *genericRunner[RunnerConfig, Input, Option, Solution],
+) handleMutexProfile(runnerConfig any,
+) (deferFunc func() error, err error) {
+	deferFunc = func() error {
+		return nil
+	}
+	if mutexProfiler, ok := runnerConfig.(MutexProfiler); ok &&
+		mutexProfiler.MutexProfilePath() != "" {
+		// Record every mutex contention event.
+		return startSampledProfile("mutex", mutexProfiler.MutexProfilePath(),
+			runtime.SetMutexProfileFraction,
+		)
 	}
 	return deferFunc, nil
 }
@@ -95,92 +273,365 @@ func (r *genericRunner[RunnerConfig, Input, Option, Solution],
 	// Memory profile.
 	if memoryProfiler, ok := runnerConfig.(MemoryProfiler); ok &&
 		memoryProfiler.MemoryProfilePath() != "" {
-		f, err := os.Create(memoryProfiler.MemoryProfilePath())
-		if err != nil {
-			return deferFunc, err
+		// Do not garbage collect the runner, so we can see in-use memory.
+		defer runtime.KeepAlive(r)
+		return deferFunc, writeHeapProfile(memoryProfiler.MemoryProfilePath())
+	}
+	return deferFunc, nil
+}
+
+// stage runs f within a span of the given name and records the returned error
+// on it.
+func (r *genericRunner[RunnerConfig, Input, Option, Solution]) stage(
+	ctx context.Context,
+	name string,
+	f func(context.Context, trace.Span) error,
+) error {
+	ctx, span := r.tracer.Start(ctx, name)
+	defer span.End()
+	err := f(ctx, span)
+	span.RecordError(err)
+	return err
+}
+
+type solutionObserverKey struct{}
+
+// solutionObserver is passed every solution the runner accepts, together with
+// the options of the run.
+type solutionObserver func(ctx context.Context, solution, option any) error
+
+// withSolutionObserver returns a context the runner passes every solution it
+// accepts to, as soon as the solution is marked and validated like the encoded
+// ones. This includes solutions that are not encoded, because only the last
+// one is. The run fails with the error of the observer.
+func withSolutionObserver(
+	ctx context.Context, observe solutionObserver,
+) context.Context {
+	return context.WithValue(ctx, solutionObserverKey{}, observe)
+}
+
+// pipe passes the solutions of the algorithm on to the encoder and returns
+// the number of solutions accepted. It cancels the run once the max number of
+// solutions is accepted and marks the solutions with the reason the run was
+// terminated, if it was. Accepted solutions are checkpointed, if checkpoints
+// are enabled, and observed, see withSolutionObserver. If solutions are
+// validated and fail the run, the run is cancelled at the first invalid
+// solution and the validation error returned.
+func (r *genericRunner[RunnerConfig, Input, Option, Solution]) pipe(
+	ctx context.Context,
+	cancel context.CancelCauseFunc,
+	checkpoints *checkpointer,
+	option Option,
+	in <-chan Solution,
+	out chan<- Solution,
+) (count int, err error) {
+	maxSolutions := 0
+	if limiter, ok := any(r.runnerConfig).(ResourceLimiter); ok {
+		maxSolutions = limiter.MaxSolutions()
+	}
+	// If only the last solution is encoded, hold it back until the algorithm
+	// is done, so it carries the termination reason. Solutions are marked with
+	// the seed and the progress of the run, too.
+	lastOnly := false
+	if limiter, ok := any(r.runnerConfig).(SolutionLimiter); ok {
+		solutions, err := limiter.Solutions()
+		lastOnly = err == nil && solutions == Last
+	}
+	observe, _ := ctx.Value(solutionObserverKey{}).(solutionObserver)
+	mark := func(solution Solution) Solution {
+		return markProgress(ctx, markSeed(ctx, markTermination(ctx, solution)))
+	}
+	// accept marks, validates and observes the solution. It returns false, if
+	// the run fails.
+	accept := func(solution Solution) (Solution, bool) {
+		solution = mark(solution)
+		if err = r.validateSolution(ctx, solution); err != nil {
+			cancel(err)
+			return solution, false
 		}
-		deferFunc = func() error {
-			return f.Close()
+		if observe != nil {
+			if err = observe(ctx, solution, option); err != nil {
+				cancel(err)
+				return solution, false
+			}
 		}
+		return solution, true
+	}
 
-		// Clean up unused objects from the heap before profiling. But do not
-		// garbage collect the runner, so we can see in-use memory.
-		runtime.GC()
-		runtime.KeepAlive(r)
-
-		if err := pprof.WriteHeapProfile(f); err != nil {
-			return deferFunc, err
+	var last Solution
+	for solution := range in {
+		if err != nil || maxSolutions > 0 && count >= maxSolutions {
+			// keep draining, so the algorithm does not block
+			continue
+		}
+		count++
+		if count == maxSolutions {
+			cancel(TerminationSolutionLimit)
+		}
+		checkpoints.update(ctx, solution)
+		if lastOnly {
+			last = solution
+			if observe != nil {
+				accept(solution)
+			}
+			continue
+		}
+		if solution, ok := accept(solution); ok {
+			out <- solution
 		}
 	}
-	return deferFunc, nil
+	if lastOnly && count > 0 && err == nil {
+		if observe != nil {
+			// the last solution is validated and observed already, it only
+			// needs to carry the termination reason.
+			out <- mark(last)
+		} else if solution, ok := accept(last); ok {
+			out <- solution
+		}
+	}
+	return count, err
+}
+
+// validateSolution validates the solution, if solutions are validated. An
+// invalid solution is logged and nil returned, unless it fails the run.
+func (r *genericRunner[RunnerConfig, Input, Option, Solution]) validateSolution(
+	ctx context.Context, solution Solution,
+) error {
+	if r.solutionValidation == ValidationOff || r.SolutionValidator == nil {
+		return nil
+	}
+	err := r.SolutionValidator(ctx, solution)
+	if err == nil {
+		return nil
+	}
+	err = fmt.Errorf("invalid solution: %w", err)
+	if r.solutionValidation == ValidationWarn {
+		log.Println(err)
+		return nil
+	}
+	return err
 }
 
 func (r *genericRunner[RunnerConfig, Input, Option, Solution]) Run(
 	ctx context.Context,
 ) (retErr error) {
+	if !r.shared {
+		// the runner owns its tracer, unless it is a copy run by the
+		// HTTPRunner or the WorkerRunner.
+		defer func() {
+			err := r.closeTracer()
+			// the first error is more important
+			if retErr == nil {
+				retErr = err
+			}
+		}()
+	}
+	if printed, err := printVersion(r.runnerConfig); printed {
+		return err
+	}
 	start := time.Now()
 	ctx = context.WithValue(ctx, Start, start)
 	ctx = context.WithValue(ctx, Data, &sync.Map{})
-	// handle CPU profile
-	deferFuncCPU, retErr := r.handleCPUProfile(r.runnerConfig)
-	if retErr != nil {
-		return retErr
-	}
+	ctx, span := r.tracer.Start(ctx, "run")
 	defer func() {
-		err := deferFuncCPU()
-		// the first error is more important
-		if retErr == nil {
-			retErr = err
-		}
+		span.RecordError(retErr)
+		span.End()
 	}()
+	// seed the random number generators of the run, see NewRand
+	ctx = withSeed(ctx, r.runnerConfig)
+	seed, _ := Seed(ctx)
+	span.SetAttribute("seed", seed)
+	// track the progress the algorithm reports, see Report
+	ctx, flushProgress := withProgress(ctx)
+	defer flushProgress()
+	// the runner cancels the run when it exceeds the configured limits
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	defer recordTermination(ctx)
+	if limit := memoryLimit(r.runnerConfig); limit > 0 {
+		defer memory.watch(limit, cancel)()
+	}
+	// handle CPU profile, execution trace, block and mutex profile
+	for _, handleProfile := range []func(any) (func() error, error){
+		r.handleCPUProfile,
+		r.handleTraceProfile,
+		r.handleBlockProfile,
+		r.handleMutexProfile,
+	} {
+		deferFunc, err := handleProfile(r.runnerConfig)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err := deferFunc()
+			// the first error is more important
+			if retErr == nil {
+				retErr = err
+			}
+		}()
+	}
 	// get IO
-	ioData, retErr := r.IOProducer(ctx, r.runnerConfig)
+	var ioData IOData
+	retErr = r.stage(ctx, "io",
+		func(ctx context.Context, span trace.Span) error {
+			var err error
+			ioData, err = r.IOProducer(ctx, r.runnerConfig)
+			if err != nil {
+				return err
+			}
+			// the input is buffered by NewIOData, so its size is known
+			if lener, ok := ioData.Input().(interface{ Len() int }); ok {
+				span.SetAttribute("input.bytes", lener.Len())
+			}
+			return nil
+		},
+	)
 	if retErr != nil {
 		return retErr
 	}
+	// the encoder closes the writer. If the run fails before encoding, the
+	// output is discarded, so remote writers do not wait for it forever.
+	encoding := false
+	defer func() {
+		if aborter, ok := ioData.Writer().(storage.Aborter); ok && !encoding {
+			_ = aborter.CloseWithError(retErr)
+		}
+	}()
 
 	if r.InputValidator != nil {
-		retErr = r.InputValidator(ctx, ioData.Input())
+		retErr = r.stage(ctx, "validate",
+			func(ctx context.Context, _ trace.Span) error {
+				return r.InputValidator(ctx, ioData.Input())
+			},
+		)
 		if retErr != nil {
 			return retErr
 		}
 	}
 
 	// decode input
-	decodedInput, retErr := r.InputDecoder(ctx, ioData.Input())
+	var decodedInput Input
+	retErr = r.stage(ctx, "decode.input",
+		func(ctx context.Context, _ trace.Span) error {
+			var err error
+			decodedInput, err = r.InputDecoder(ctx, ioData.Input())
+			return err
+		},
+	)
 	if retErr != nil {
 		return retErr
 	}
 
+	// decode initial solution if provided
+	if initialSolutioner, ok := ioData.(InitialSolutioner); ok &&
+		initialSolutioner.InitialSolution() != nil {
+		var initialSolution Solution
+		retErr = r.stage(ctx, "decode.initial_solution",
+			func(ctx context.Context, _ trace.Span) error {
+				var err error
+				initialSolution, err = r.SolutionDecoder(
+					ctx, initialSolutioner.InitialSolution(),
+				)
+				return err
+			},
+		)
+		if retErr != nil {
+			return retErr
+		}
+		ctx = context.WithValue(ctx, initialSolutionKey{}, initialSolution)
+	}
+
+	// resume from the checkpoint of the input, if there is one
+	var checkpoints *checkpointer
+	if config, ok := checkpointConfig(r.runnerConfig); ok {
+		retErr = r.stage(ctx, "checkpoint.resume",
+			func(_ context.Context, _ trace.Span) error {
+				var err error
+				ctx, checkpoints, err = r.resume(ctx, config, ioData.Input())
+				return err
+			},
+		)
+		if retErr != nil {
+			return retErr
+		}
+	}
+
 	// use options configured in runner via flags and environment variables
 	decodedOption := r.flagParsedOption
 	// decode option if provided
-	tempOption, err := r.OptionDecoder(ctx, ioData.Option())
-	if err != nil {
-		return err
-	}
-	var defaultOption Option
-	// if option is not default, use it
-	if !reflect.DeepEqual(tempOption, defaultOption) {
-		decodedOption = tempOption
+	retErr = r.stage(ctx, "decode.option",
+		func(ctx context.Context, _ trace.Span) error {
+			tempOption, err := r.OptionDecoder(ctx, ioData.Option())
+			if err != nil {
+				return err
+			}
+			var defaultOption Option
+			// if option is not default, use it
+			if !reflect.DeepEqual(tempOption, defaultOption) {
+				decodedOption = tempOption
+			}
+			return nil
+		},
+	)
+	if retErr != nil {
+		return retErr
 	}
 
 	// run algorithm
 	solutions := make(chan Solution)
 	errs := make(chan error, 1)
 	go func() {
-		defer close(solutions)
 		defer close(errs)
-		retErr = r.Algorithm(ctx, decodedInput, decodedOption, solutions)
-		if retErr != nil {
-			errs <- retErr
-			return
+		solve := func(ctx context.Context, span trace.Span) error {
+			count := 0
+			var invalid error
+			out := make(chan Solution)
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				defer close(solutions)
+				count, invalid = r.pipe(
+					ctx, cancel, checkpoints, decodedOption, out, solutions,
+				)
+			}()
+			checkpoints.start(ctx)
+			var running map[string]string
+			if leakChecker, ok := any(r.runnerConfig).(LeakChecker); ok &&
+				leakChecker.CheckLeaks() {
+				running = goroutines()
+			}
+			err := r.Algorithm(ctx, decodedInput, decodedOption, out)
+			// the algorithm finished, unless it failed or the run was
+			// stopped early.
+			finished := err == nil
+			if isTerminationError(ctx, err) {
+				err = nil
+			}
+			close(out)
+			<-done
+			checkpoints.finish(ctx, finished && ctx.Err() == nil)
+			span.SetAttribute("solutions", count)
+			if running != nil {
+				reportLeakedGoroutines(running)
+			}
+			if invalid != nil {
+				return invalid
+			}
+			return err
+		}
+		if err := r.stage(ctx, "solve", solve); err != nil {
+			errs <- err
 		}
 	}()
 
 	// encode solutions
-	retErr = r.Encoder.Encode(
-		ctx, solutions, ioData.Writer(), r.runnerConfig, decodedOption,
+	encoding = true
+	retErr = r.stage(ctx, "encode",
+		func(ctx context.Context, _ trace.Span) error {
+			return r.Encoder.Encode(
+				ctx, solutions, ioData.Writer(), r.runnerConfig, decodedOption,
+			)
+		},
 	)
 	if retErr != nil {
 		return retErr
@@ -204,6 +655,31 @@ func (r *genericRunner[RunnerConfig, Input, Option, Solution]) Run(
 	return <-errs
 }
 
+// cloner is implemented by runners that can be copied, so concurrent runs can
+// be configured independently of each other.
+type cloner[RunnerConfig, Input, Option, Solution any] interface {
+	clone() Runner[RunnerConfig, Input, Option, Solution]
+}
+
+// cloneRunner returns a copy of the runner, if it can be copied. Otherwise the
+// runner itself is returned.
+func cloneRunner[RunnerConfig, Input, Option, Solution any](
+	runner Runner[RunnerConfig, Input, Option, Solution],
+) Runner[RunnerConfig, Input, Option, Solution] {
+	if c, ok := runner.(cloner[RunnerConfig, Input, Option, Solution]); ok {
+		return c.clone()
+	}
+	return runner
+}
+
+func (r *genericRunner[
+	RunnerConfig, Input, Option, Solution,
+]) clone() Runner[RunnerConfig, Input, Option, Solution] {
+	c := *r
+	c.shared = true
+	return &c
+}
+
 func (r *genericRunner[RunnerConfig, Input, Option, Solution]) SetIOProducer(
 	ioProducer IOProducer[RunnerConfig],
 ) {
@@ -228,6 +704,18 @@ func (r *genericRunner[RunnerConfig, Input, Option, Solution]) SetOptionDecoder(
 	r.OptionDecoder = decoder
 }
 
+func (r *genericRunner[
+	RunnerConfig, Input, Option, Solution,
+]) SetSolutionDecoder(decoder Decoder[Solution]) {
+	r.SolutionDecoder = decoder
+}
+
+func (r *genericRunner[
+	RunnerConfig, Input, Option, Solution,
+]) SetSolutionValidator(validator Validator[Solution]) {
+	r.SolutionValidator = validator
+}
+
 func (r *genericRunner[RunnerConfig, Input, Option, Solution]) SetAlgorithm(
 	algorithm Algorithm[Input, Option, Solution],
 ) {
@@ -240,6 +728,12 @@ func (r *genericRunner[RunnerConfig, Input, Option, Solution]) SetEncoder(
 	r.Encoder = encoder
 }
 
+func (r *genericRunner[RunnerConfig, Input, Option, Solution]) SetTracer(
+	tracer trace.Tracer,
+) {
+	r.tracer = tracer
+}
+
 func (r *genericRunner[
 	RunnerConfig, Input, Option, Solution,
 ]) GetEncoder() Encoder[Solution, Option] {
@@ -7,15 +7,18 @@ import (
 	"github.com/nextmv-io/sdk/run/encode"
 )
 
-// Decode sets the decoder of a HTTPRunner.
+// Decode sets the decoder of the input and initial solution of a HTTPRunner.
 func Decode[Input, Option, Solution any, Decoder decode.Decoder](
 	d Decoder,
 ) func(
 	run.Runner[run.HTTPRunnerConfig, Input, Option, Solution],
 ) {
-	return run.InputDecode[run.HTTPRunnerConfig, Input, Option, Solution](
-		run.GenericDecoder[Input](d),
-	)
+	return func(r run.Runner[run.HTTPRunnerConfig, Input, Option, Solution]) {
+		r.SetInputDecoder(run.GenericDecoder[Input](d))
+		run.SolutionDecode[run.HTTPRunnerConfig, Input, Option](
+			run.GenericDecoder[Solution](d),
+		)(r)
+	}
 }
 
 // Encode sets the encoder of a HTTPRunner.
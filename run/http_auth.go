@@ -0,0 +1,325 @@
+package run
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Headers used to authenticate requests to the HTTPRunner.
+const (
+	// APIKeyHeader carries the API key of a request. Alternatively, the key
+	// can be sent as bearer token in the Authorization header.
+	APIKeyHeader = "X-API-Key"
+	// SignatureHeader carries the signature of a request as "<id>:<hex>",
+	// where id identifies the API key and hex is the HMAC-SHA256 of the
+	// timestamp, the method, the request URI and the body, separated by new
+	// lines, keyed with the API key.
+	SignatureHeader = "X-Signature"
+	// TimestampHeader carries the time a signed request was created at in
+	// seconds since the Unix epoch.
+	TimestampHeader = "X-Timestamp"
+)
+
+// Principal is an authenticated client of the HTTPRunner.
+type Principal struct {
+	// ID identifies the client.
+	ID string
+	// MaxParallel is the max number of parallel requests of the client, 0
+	// means it is only limited by the max number of parallel requests of the
+	// runner.
+	MaxParallel int
+}
+
+// Authenticator authenticates the requests to the HTTPRunner.
+type Authenticator interface {
+	// Authenticate returns the client that sent the request. An error is
+	// returned, if the request is not authenticated.
+	Authenticate(req *http.Request) (Principal, error)
+}
+
+// AuthenticatorFunc is a function implementing Authenticator.
+type AuthenticatorFunc func(req *http.Request) (Principal, error)
+
+// Authenticate calls f(req).
+func (f AuthenticatorFunc) Authenticate(req *http.Request) (Principal, error) {
+	return f(req)
+}
+
+// errUnauthenticated is returned when a request carries no credentials of an
+// Authenticator.
+var errUnauthenticated = errors.New("request is not authenticated")
+
+// APIKey is a static key a client authenticates with.
+type APIKey struct {
+	ID          string `json:"id"`
+	Key         string `json:"key"`
+	MaxParallel int    `json:"max_parallel"`
+}
+
+func (k APIKey) principal() Principal {
+	return Principal{ID: k.ID, MaxParallel: k.MaxParallel}
+}
+
+// ReadAPIKeys reads API keys from a JSON file holding an array of objects with
+// the fields id, key and max_parallel.
+func ReadAPIKeys(path string) ([]APIKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var keys []APIKey
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("reading API keys %s: %w", path, err)
+	}
+	for i, key := range keys {
+		if key.ID == "" || key.Key == "" {
+			return nil, fmt.Errorf(
+				"reading API keys %s: key %d has no id or key", path, i,
+			)
+		}
+	}
+	return keys, nil
+}
+
+// APIKeys authenticates requests sending one of the given keys in the
+// X-API-Key header or as bearer token.
+func APIKeys(keys []APIKey) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) (Principal, error) {
+		key := req.Header.Get(APIKeyHeader)
+		if token, ok := strings.CutPrefix(
+			req.Header.Get("Authorization"), "Bearer ",
+		); ok {
+			key = token
+		}
+		if key == "" {
+			return Principal{}, errUnauthenticated
+		}
+		for _, apiKey := range keys {
+			if subtle.ConstantTimeCompare([]byte(key), []byte(apiKey.Key)) == 1 {
+				return apiKey.principal(), nil
+			}
+		}
+		return Principal{}, errors.New("invalid API key")
+	})
+}
+
+// maxSignedBodySize is the max size in bytes of the body of a signed request,
+// which is read into memory to verify the signature.
+const maxSignedBodySize = 64 << 20
+
+// HMACSignatures authenticates requests signed with one of the given keys,
+// see SignatureHeader. Requests with a timestamp deviating more than maxSkew
+// from the current time are rejected. Each signature is accepted once, so a
+// captured request cannot be replayed while its timestamp is in range. Clients
+// sending the same request twice within a second need to wait for the next
+// timestamp. The body is only read once the key and the timestamp are
+// verified, and at most 64 MiB of it.
+func HMACSignatures(keys []APIKey, maxSkew time.Duration) Authenticator {
+	seen := &signatureCache{expires: map[string]time.Time{}}
+	return AuthenticatorFunc(func(req *http.Request) (Principal, error) {
+		signature := req.Header.Get(SignatureHeader)
+		if signature == "" {
+			return Principal{}, errUnauthenticated
+		}
+		id, signatureHex, ok := strings.Cut(signature, ":")
+		if !ok {
+			return Principal{}, errors.New("malformed signature")
+		}
+		var key *APIKey
+		for i := range keys {
+			if keys[i].ID == id {
+				key = &keys[i]
+				break
+			}
+		}
+		if key == nil {
+			return Principal{}, errors.New("invalid signature")
+		}
+		seconds, err := strconv.ParseInt(req.Header.Get(TimestampHeader), 10, 64)
+		if err != nil {
+			return Principal{}, errors.New("malformed timestamp")
+		}
+		skew := time.Since(time.Unix(seconds, 0))
+		if skew > maxSkew || skew < -maxSkew {
+			return Principal{}, errors.New("timestamp is out of range")
+		}
+
+		// read one byte more than allowed to detect exceeding the limit.
+		body, err := io.ReadAll(io.LimitReader(req.Body, maxSignedBodySize+1))
+		if err != nil {
+			return Principal{}, err
+		}
+		if len(body) > maxSignedBodySize {
+			return Principal{}, fmt.Errorf(
+				"%w: signed body exceeds %d bytes",
+				ErrInputTooLarge, maxSignedBodySize,
+			)
+		}
+		if err := req.Body.Close(); err != nil {
+			return Principal{}, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		expected := Sign(
+			key.Key, seconds, req.Method, req.URL.RequestURI(), body,
+		)
+		if !hmac.Equal([]byte(signatureHex), []byte(expected)) {
+			return Principal{}, errors.New("invalid signature")
+		}
+		if !seen.add(signature, time.Unix(seconds, 0).Add(maxSkew)) {
+			return Principal{}, errors.New("signature was used before")
+		}
+		return key.principal(), nil
+	})
+}
+
+// signatureCache holds the signatures of accepted requests until their
+// timestamp is out of range. It is safe for concurrent use.
+type signatureCache struct {
+	mutex   sync.Mutex
+	expires map[string]time.Time
+	swept   time.Time
+}
+
+// add adds the signature until it expires. It returns false, if the signature
+// was added before.
+func (c *signatureCache) add(signature string, expires time.Time) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	now := time.Now()
+	if expiry, ok := c.expires[signature]; ok && now.Before(expiry) {
+		return false
+	}
+	// drop expired signatures at most once per second.
+	if now.Sub(c.swept) >= time.Second {
+		for s, expiry := range c.expires {
+			if !now.Before(expiry) {
+				delete(c.expires, s)
+			}
+		}
+		c.swept = now
+	}
+	c.expires[signature] = expires
+	return true
+}
+
+// Sign returns the hex encoded signature of a request, see SignatureHeader.
+func Sign(key string, timestamp int64, method, uri string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	fmt.Fprintf(mac, "%d\n%s\n%s\n", timestamp, method, uri)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ClientCertificates authenticates requests by the verified TLS client
+// certificate, identifying the client by the common name of the certificate.
+// The max number of parallel requests is taken from the key with the same id,
+// if any.
+func ClientCertificates(keys []APIKey) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) (Principal, error) {
+		if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 {
+			return Principal{}, errUnauthenticated
+		}
+		principal := Principal{
+			ID: req.TLS.VerifiedChains[0][0].Subject.CommonName,
+		}
+		for _, apiKey := range keys {
+			if apiKey.ID == principal.ID {
+				principal.MaxParallel = apiKey.MaxParallel
+			}
+		}
+		return principal, nil
+	})
+}
+
+// AnyOf authenticates requests with the first of the given authenticators the
+// request carries credentials of.
+func AnyOf(authenticators ...Authenticator) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) (Principal, error) {
+		for _, authenticator := range authenticators {
+			principal, err := authenticator.Authenticate(req)
+			if errors.Is(err, errUnauthenticated) {
+				continue
+			}
+			return principal, err
+		}
+		return Principal{}, errUnauthenticated
+	})
+}
+
+// clientTLSConfig returns a copy of the given TLS configuration requiring
+// clients to present a certificate signed by one of the certificate
+// authorities in the given file. A nil configuration is replaced by a default
+// one.
+func clientTLSConfig(base *tls.Config, caPath string) (*tls.Config, error) {
+	pem, err := os.ReadFile(caPath)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", caPath)
+	}
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if base != nil {
+		config = base.Clone()
+	}
+	config.ClientCAs = pool
+	config.ClientAuth = tls.RequireAndVerifyClientCert
+	return config, nil
+}
+
+// quotas limits the number of parallel requests per client.
+type quotas struct {
+	mutex sync.Mutex
+	slots map[string]chan struct{}
+}
+
+// acquire takes a slot of the client. It returns false, if the client has no
+// free slot. Taken slots need to be released.
+func (q *quotas) acquire(principal Principal) bool {
+	if principal.MaxParallel <= 0 {
+		return true
+	}
+	q.mutex.Lock()
+	if q.slots == nil {
+		q.slots = map[string]chan struct{}{}
+	}
+	slots, ok := q.slots[principal.ID]
+	if !ok {
+		slots = make(chan struct{}, principal.MaxParallel)
+		q.slots[principal.ID] = slots
+	}
+	q.mutex.Unlock()
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (q *quotas) release(principal Principal) {
+	if principal.MaxParallel <= 0 {
+		return
+	}
+	q.mutex.Lock()
+	slots := q.slots[principal.ID]
+	q.mutex.Unlock()
+	<-slots
+}
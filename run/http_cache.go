@@ -0,0 +1,178 @@
+package run
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Headers of the result cache of the HTTPRunner.
+const (
+	// CacheHeader reports whether the response was served from the cache,
+	// its value is either "hit" or "miss".
+	CacheHeader = "X-Cache"
+	cacheHit    = "hit"
+	cacheMiss   = "miss"
+)
+
+// resultCache is a least recently used cache of encoded outputs with a time
+// to live. It is safe for concurrent use.
+type resultCache struct {
+	mutex   sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	// order holds the entries, the most recently used first.
+	order *list.List
+}
+
+type cachedResult struct {
+	key         string
+	contentType string
+	body        []byte
+	expires     time.Time
+}
+
+// newResultCache creates a cache holding up to size results, each for the
+// given time to live. A ttl of 0 keeps results until they are evicted.
+func newResultCache(size int, ttl time.Duration) *resultCache {
+	return &resultCache{
+		size:    size,
+		ttl:     ttl,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+func (c *resultCache) get(key string) (cachedResult, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return cachedResult{}, false
+	}
+	result := element.Value.(cachedResult)
+	if !result.expires.IsZero() && time.Now().After(result.expires) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return cachedResult{}, false
+	}
+	c.order.MoveToFront(element)
+	return result, true
+}
+
+func (c *resultCache) put(key string, contentType string, body []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	result := cachedResult{key: key, contentType: contentType, body: body}
+	if c.ttl > 0 {
+		result.expires = time.Now().Add(c.ttl)
+	}
+	if element, ok := c.entries[key]; ok {
+		element.Value = result
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(result)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(cachedResult).key)
+	}
+}
+
+// cacheKey reads the body of the request and returns the key of its result in
+// the cache. The body is replaced, so it can be read again. The key is a hash
+// of the path, the normalized input and the options given as query
+// parameters. The parts of multipart/form-data requests are hashed instead of
+// the body, because their boundary is random.
+func cacheKey(req *http.Request) (string, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return "", err
+	}
+	if err := req.Body.Close(); err != nil {
+		return "", err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	hash := sha256.New()
+	// the path selects the algorithm.
+	hash.Write([]byte(req.URL.Path))
+	hash.Write([]byte{0})
+	mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err == nil && mediaType == "multipart/form-data" {
+		reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return "", httpError{status: http.StatusBadRequest, err: err}
+			}
+			data, err := io.ReadAll(part)
+			if err != nil {
+				return "", httpError{status: http.StatusBadRequest, err: err}
+			}
+			hash.Write([]byte(part.FormName()))
+			hash.Write([]byte{0})
+			hash.Write(normalizeJSON(data))
+			hash.Write([]byte{0})
+		}
+	} else {
+		hash.Write(normalizeJSON(body))
+		hash.Write([]byte{0})
+	}
+	// Encode sorts the query parameters by key.
+	hash.Write([]byte(req.URL.Query().Encode()))
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// normalizeJSON returns the data with JSON normalized, so formatting and the
+// order of keys do not matter. Other data is returned as is.
+func normalizeJSON(data []byte) []byte {
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if decoder.Decode(&value) == nil && !decoder.More() {
+		if normalized, err := json.Marshal(value); err == nil {
+			return normalized
+		}
+	}
+	return data
+}
+
+// bypassesCache reports whether the request asks for a fresh result.
+func bypassesCache(req *http.Request) bool {
+	for _, value := range req.Header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(directive), "no-cache") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// recordingResponseWriter records the body written to the response, so it can
+// be cached.
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingResponseWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
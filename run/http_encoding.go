@@ -0,0 +1,75 @@
+package run
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// acceptsGzip reports whether the client accepts gzipped responses, i.e. the
+// Accept-Encoding header lists gzip without a quality value of 0.
+func acceptsGzip(req *http.Request) bool {
+	for _, value := range req.Header.Values("Accept-Encoding") {
+		for _, coding := range strings.Split(value, ",") {
+			name, params, _ := strings.Cut(coding, ";")
+			if !strings.EqualFold(strings.TrimSpace(name), "gzip") {
+				continue
+			}
+			quality, found := strings.CutPrefix(strings.TrimSpace(params), "q=")
+			if !found {
+				return true
+			}
+			q, err := strconv.ParseFloat(quality, 64)
+			return err == nil && q > 0
+		}
+	}
+	return false
+}
+
+// gzipResponseWriter gzips the body of the response as it is written, so the
+// output is not buffered. The Content-Encoding header is only set once the
+// body is written, so errors reported before are sent uncompressed.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	once   sync.Once
+	writer *gzip.Writer
+}
+
+func newGzipResponseWriter(w http.ResponseWriter) *gzipResponseWriter {
+	// the response differs depending on the header, also when it is not
+	// compressed.
+	w.Header().Add("Vary", "Accept-Encoding")
+	return &gzipResponseWriter{ResponseWriter: w}
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	if w.writer == nil {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Del("Content-Length")
+		w.writer = gzip.NewWriter(w.ResponseWriter)
+	}
+	return w.writer.Write(b)
+}
+
+// Flush flushes the compressed data written so far to the client.
+func (w *gzipResponseWriter) Flush() {
+	if w.writer != nil {
+		// errors resurface when writing or closing.
+		_ = w.writer.Flush()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Close writes the end of the gzip stream. It is safe to call it repeatedly.
+func (w *gzipResponseWriter) Close() (err error) {
+	w.once.Do(func() {
+		if w.writer != nil {
+			err = w.writer.Close()
+		}
+	})
+	return err
+}
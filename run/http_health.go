@@ -0,0 +1,35 @@
+package run
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// healthPath is the path of the health endpoint of the HTTPRunner. It responds
+// with 200 OK as long as the server is up, reporting the number of active runs
+// and the names of the routes, see AddRoute.
+const healthPath = "/health"
+
+// health is the response of the health endpoint.
+type health struct {
+	Status     string   `json:"status"`
+	ActiveRuns int      `json:"active_runs"`
+	Routes     []string `json:"routes"`
+}
+
+// serveHealth writes the health of the HTTPRunner.
+func serveHealth(
+	w http.ResponseWriter, req *http.Request, activeRuns int, routes []string,
+) error {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(health{
+		Status:     "ok",
+		ActiveRuns: activeRuns,
+		Routes:     routes,
+	})
+}
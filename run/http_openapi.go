@@ -0,0 +1,438 @@
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	humaSchema "github.com/danielgtaylor/huma/schema"
+)
+
+// openAPIPath is the path of the OpenAPI document of the HTTPRunner. It
+// describes the solve endpoints with the schemas of the inputs, the options
+// and the solutions.
+const openAPIPath = "/openapi.json"
+
+// openAPI is an OpenAPI 3 document, reduced to the parts the HTTPRunner uses.
+type openAPI struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       openAPIInfo                     `json:"info"`
+	Paths      map[string]map[string]operation `json:"paths"`
+	Components openAPIComponents               `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*humaSchema.Schema `json:"schemas,omitempty"`
+	SecuritySchemes map[string]securityScheme     `json:"securitySchemes,omitempty"`
+}
+
+type securityScheme struct {
+	Type   string `json:"type"`
+	Name   string `json:"name,omitempty"`
+	In     string `json:"in,omitempty"`
+	Scheme string `json:"scheme,omitempty"`
+}
+
+type operation struct {
+	Summary     string                                     `json:"summary"`
+	Description string                                     `json:"description,omitempty"`
+	Parameters  []parameter                                `json:"parameters,omitempty"`
+	RequestBody *requestBody                               `json:"requestBody,omitempty"`
+	Responses   map[string]response                        `json:"responses"`
+	Callbacks   map[string]map[string]map[string]operation `json:"callbacks,omitempty"`
+	Security    []map[string][]string                      `json:"security,omitempty"`
+}
+
+type parameter struct {
+	Name        string             `json:"name"`
+	In          string             `json:"in"`
+	Description string             `json:"description,omitempty"`
+	Required    bool               `json:"required,omitempty"`
+	Schema      *humaSchema.Schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type response struct {
+	Description string               `json:"description"`
+	Headers     map[string]header    `json:"headers,omitempty"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+}
+
+type header struct {
+	Description string             `json:"description,omitempty"`
+	Schema      *humaSchema.Schema `json:"schema"`
+}
+
+type mediaType struct {
+	Schema *humaSchema.Schema `json:"schema"`
+}
+
+// openAPIEndpoint describes the solve endpoint of an algorithm.
+type openAPIEndpoint struct {
+	path string
+	// prefix is prepended to the names of the schemas of the algorithm in
+	// the components, so the schemas of several algorithms do not clash.
+	prefix   string
+	input    *humaSchema.Schema
+	solution *humaSchema.Schema
+	// options are the query parameters of the options.
+	options []parameter
+}
+
+// newOpenAPIEndpoint describes the solve endpoint at path. The name of the
+// algorithm prefixes its schema names, if it is not empty.
+func newOpenAPIEndpoint[Input, Option, Solution any](
+	path, name string,
+) (openAPIEndpoint, error) {
+	input, err := humaSchema.Generate(reflect.TypeOf(new(Input)))
+	if err != nil {
+		return openAPIEndpoint{}, fmt.Errorf("generating input schema: %w", err)
+	}
+	solution, err := humaSchema.Generate(reflect.TypeOf(new(Solution)))
+	if err != nil {
+		return openAPIEndpoint{},
+			fmt.Errorf("generating solution schema: %w", err)
+	}
+	options, err := optionParameters(reflect.TypeOf(new(Option)).Elem(), "")
+	if err != nil {
+		return openAPIEndpoint{},
+			fmt.Errorf("generating option parameters: %w", err)
+	}
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+	return openAPIEndpoint{
+		path:     path,
+		prefix:   prefix,
+		input:    input,
+		solution: solution,
+		options:  options,
+	}, nil
+}
+
+// openAPIDocument returns the OpenAPI document of an HTTPRunner with the
+// given solve endpoints. If secured is true, the operations require an API
+// key.
+func openAPIDocument(
+	secured bool, endpoints ...openAPIEndpoint,
+) (openAPI, error) {
+	status, err := humaSchema.Generate(reflect.TypeOf(runStatus{}))
+	if err != nil {
+		return openAPI{}, fmt.Errorf("generating run status schema: %w", err)
+	}
+	document := openAPI{
+		OpenAPI: "3.0.3",
+		Info: openAPIInfo{
+			Title:   filepath.Base(os.Args[0]),
+			Version: "1.0.0",
+		},
+		Paths: map[string]map[string]operation{
+			runsPath + "{request_id}": {
+				"get": statusOperation(),
+			},
+			runsPath + "{request_id}/" + runProgressAction: {
+				"get": progressOperation(),
+			},
+			runsPath + "{request_id}/" + runCancelAction: {
+				"post": cancelOperation(),
+			},
+		},
+		Components: openAPIComponents{
+			Schemas: map[string]*humaSchema.Schema{"RunStatus": status},
+		},
+	}
+	for _, endpoint := range endpoints {
+		document.Paths[endpoint.path] = map[string]operation{
+			"post": solveOperation(endpoint),
+		}
+		document.Components.Schemas[endpoint.prefix+"Input"] = endpoint.input
+		document.Components.Schemas[endpoint.prefix+"Solution"] =
+			endpoint.solution
+	}
+	if secured {
+		document.Components.SecuritySchemes = map[string]securityScheme{
+			"apiKey": {Type: "apiKey", Name: APIKeyHeader, In: "header"},
+			"bearer": {Type: "http", Scheme: "bearer"},
+		}
+		security := []map[string][]string{{"apiKey": {}}, {"bearer": {}}}
+		for _, operations := range document.Paths {
+			for method, op := range operations {
+				op.Security = security
+				operations[method] = op
+			}
+		}
+	}
+	return document, nil
+}
+
+// solveOperation describes running the algorithm of the endpoint.
+func solveOperation(endpoint openAPIEndpoint) operation {
+	ref := func(name string) *humaSchema.Schema {
+		return &humaSchema.Schema{
+			Ref: "#/components/schemas/" + endpoint.prefix + name,
+		}
+	}
+	text := &humaSchema.Schema{Type: humaSchema.TypeString}
+	requestID := header{
+		Description: "Identifies the run.",
+		Schema:      &humaSchema.Schema{Type: humaSchema.TypeString, Format: "uuid"},
+	}
+	parameters := append([]parameter{{
+		Name: "callback_url",
+		In:   "header",
+		Description: "Async runs only: the URL the solution is posted to, " +
+			"overriding the configured one.",
+		Schema: &humaSchema.Schema{Type: humaSchema.TypeString, Format: "uri"},
+	}}, endpoint.options...)
+
+	return operation{
+		Summary: "Run the algorithm on the input.",
+		Description: "Sync runs respond with the solution. Async runs respond " +
+			"with the request id and post the solution to the callback_url.",
+		Parameters: parameters,
+		RequestBody: &requestBody{
+			Required: true,
+			Content: map[string]mediaType{
+				"application/json": {Schema: ref("Input")},
+				"multipart/form-data": {Schema: &humaSchema.Schema{
+					Type: humaSchema.TypeObject,
+					Properties: map[string]*humaSchema.Schema{
+						InputFormField:           ref("Input"),
+						InitialSolutionFormField: ref("Solution"),
+					},
+					Required: []string{InputFormField},
+				}},
+			},
+		},
+		Responses: map[string]response{
+			"200": {
+				Description: "The solution of a sync run or the request id " +
+					"of an async run.",
+				Headers: map[string]header{
+					"request_id": requestID,
+					CacheHeader: {
+						Description: "Whether the solution was served from " +
+							"the cache, either hit or miss.",
+						Schema: text,
+					},
+				},
+				Content: map[string]mediaType{
+					"application/json": {Schema: ref("Solution")},
+					"text/plain":       {Schema: text},
+				},
+			},
+			"400": errorResponse("The request is malformed."),
+			"401": errorResponse("The request is not authenticated."),
+			"409": errorResponse("The requested profile is already taken."),
+			"413": errorResponse("The input exceeds the size limit."),
+			"429": errorResponse("The rate limit or the max number of " +
+				"parallel requests is exceeded."),
+			"500": errorResponse("The input is invalid or the run failed."),
+		},
+		Callbacks: map[string]map[string]map[string]operation{
+			"solution": {
+				"{$request.header.callback_url}": {
+					"post": {
+						Summary: "Receive the solution of an async run.",
+						Parameters: []parameter{{
+							Name:        "request_id",
+							In:          "header",
+							Description: requestID.Description,
+							Required:    true,
+							Schema:      requestID.Schema,
+						}},
+						RequestBody: &requestBody{
+							Required: true,
+							Content: map[string]mediaType{
+								"application/json": {
+									Schema: ref("Solution"),
+								},
+							},
+						},
+						Responses: map[string]response{
+							"200": {Description: "The solution was received."},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// runOperation describes an operation on an active run, see runsPath.
+func runOperation(summary string, responses map[string]response) operation {
+	responses["401"] = errorResponse("The request is not authenticated.")
+	responses["404"] = errorResponse("No active run of the client has the " +
+		"request id.")
+	return operation{
+		Summary: summary,
+		Parameters: []parameter{{
+			Name:     "request_id",
+			In:       "path",
+			Required: true,
+			Schema:   &humaSchema.Schema{Type: humaSchema.TypeString},
+		}},
+		Responses: responses,
+	}
+}
+
+func statusOperation() operation {
+	return runOperation("Get the status and progress of a run.",
+		map[string]response{
+			"200": {
+				Description: "The status of the run.",
+				Content: map[string]mediaType{
+					"application/json": {Schema: &humaSchema.Schema{
+						Ref: "#/components/schemas/RunStatus",
+					}},
+				},
+			},
+		},
+	)
+}
+
+func progressOperation() operation {
+	return runOperation("Stream the progress of a run.", map[string]response{
+		"200": {
+			Description: "Server-sent events of type progress, carrying the " +
+				"progress as JSON, and a final event of type done.",
+			Content: map[string]mediaType{
+				"text/event-stream": {
+					Schema: &humaSchema.Schema{Type: humaSchema.TypeString},
+				},
+			},
+		},
+	})
+}
+
+func cancelOperation() operation {
+	op := runOperation("Cancel a run.", map[string]response{
+		"202": {Description: "The run is cancelled."},
+	})
+	op.Description = "The best solution found so far is delivered, marked " +
+		"as cancelled in its statistics."
+	return op
+}
+
+func errorResponse(description string) response {
+	return response{
+		Description: description,
+		Content: map[string]mediaType{
+			"text/plain": {Schema: &humaSchema.Schema{Type: humaSchema.TypeString}},
+		},
+	}
+}
+
+// optionParameters returns the query parameters of the options of type t, as
+// decoded by QueryParamDecoder. Fields of nested structs are prefixed with
+// the name of the struct field.
+func optionParameters(t reflect.Type, prefix string) ([]parameter, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, nil
+	}
+	var parameters []parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := queryParamName(field)
+		if name == "-" {
+			continue
+		}
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct &&
+			fieldType != reflect.TypeOf(time.Time{}) {
+			nestedPrefix := prefix
+			if !field.Anonymous {
+				nestedPrefix += name + "."
+			}
+			nested, err := optionParameters(fieldType, nestedPrefix)
+			if err != nil {
+				return nil, err
+			}
+			parameters = append(parameters, nested...)
+			continue
+		}
+		s, err := humaSchema.Generate(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("option %s: %w", prefix+name, err)
+		}
+		if value, ok := field.Tag.Lookup("default"); ok {
+			s.Default = defaultValue(fieldType, value)
+		}
+		parameters = append(parameters, parameter{
+			Name:        prefix + name,
+			In:          "query",
+			Description: field.Tag.Get("usage"),
+			Schema:      s,
+		})
+	}
+	return parameters, nil
+}
+
+// queryParamName returns the name of the query parameter of the field. Like
+// flags, it is the lowercased field name unless a schema tag sets it. Query
+// parameters are matched case-insensitively.
+func queryParamName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("schema"), ","); name != "" {
+		return name
+	}
+	return strings.ToLower(field.Name)
+}
+
+// defaultValue converts the default tag to the value in the query, i.e.
+// durations in nanoseconds.
+func defaultValue(t reflect.Type, tag string) any {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		if d, err := time.ParseDuration(tag); err == nil {
+			return d.Nanoseconds()
+		}
+	}
+	if t.Kind() == reflect.String {
+		return tag
+	}
+	var value any
+	if err := json.Unmarshal([]byte(tag), &value); err != nil {
+		return tag
+	}
+	return value
+}
+
+// serveOpenAPI writes the OpenAPI document.
+func serveOpenAPI(
+	w http.ResponseWriter, req *http.Request, document func() (openAPI, error),
+) error {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil
+	}
+	d, err := document()
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(d)
+}
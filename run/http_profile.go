@@ -0,0 +1,128 @@
+package run
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProfileQueryParam is the query parameter requesting profiles of a single
+// run from the HTTPRunner, e.g. ?profile=cpu or ?profile=cpu,heap. The
+// profiles are stored in the configured profile directory as
+// <request_id>.cpu.pprof and <request_id>.heap.pprof and served by the admin
+// server under /debug/profiles/.
+const ProfileQueryParam = "profile"
+
+const (
+	cpuRunProfile  = "cpu"
+	heapRunProfile = "heap"
+)
+
+// popRunProfiles removes the profile query parameter from the request, so it
+// is not mistaken for an option, and returns a profiler for the requested
+// profiles.
+func popRunProfiles(req *http.Request, dir string) (runProfiler, error) {
+	query := req.URL.Query()
+	values, ok := query[ProfileQueryParam]
+	if !ok {
+		return runProfiler{}, nil
+	}
+	query.Del(ProfileQueryParam)
+	req.URL.RawQuery = query.Encode()
+
+	if dir == "" {
+		return runProfiler{}, httpError{
+			status: http.StatusBadRequest,
+			err: errors.New(
+				"profiling runs requires runner.profile.dir to be configured",
+			),
+		}
+	}
+	profiler := runProfiler{dir: dir}
+	for _, value := range values {
+		for _, kind := range strings.Split(value, ",") {
+			switch kind {
+			case cpuRunProfile:
+				profiler.cpu = true
+			case heapRunProfile:
+				profiler.heap = true
+			default:
+				return runProfiler{}, httpError{
+					status: http.StatusBadRequest,
+					err: fmt.Errorf(
+						`%s must be "cpu" or "heap", got %q`,
+						ProfileQueryParam, kind,
+					),
+				}
+			}
+		}
+	}
+	return profiler, nil
+}
+
+// runProfiler profiles a single run of the HTTPRunner.
+type runProfiler struct {
+	dir  string
+	cpu  bool
+	heap bool
+}
+
+func (p runProfiler) path(requestID, kind string) string {
+	return filepath.Join(p.dir, requestID+"."+kind+".pprof")
+}
+
+// start starts profiling the run with the given request ID. The returned
+// function stops profiling and has to be called once the run is done.
+func (p runProfiler) start(requestID string) (stop func() error, err error) {
+	stopCPU := func() error {
+		return nil
+	}
+	if p.cpu {
+		path := p.path(requestID, cpuRunProfile)
+		stopCPU, err = startCPUProfile(path)
+		if err != nil {
+			// The CPU profiler is process wide, so the most likely reason is
+			// another run being profiled at the same time.
+			err = errors.Join(err, stopCPU(), os.Remove(path))
+			return nil, httpError{status: http.StatusConflict, err: err}
+		}
+	}
+	return func() error {
+		err := stopCPU()
+		if p.heap {
+			err = errors.Join(
+				err, writeHeapProfile(p.path(requestID, heapRunProfile)),
+			)
+		}
+		return err
+	}, nil
+}
+
+// adminHandler returns the handler of the admin server. It exposes the pprof
+// endpoints and the profiles stored for single runs.
+func adminHandler(profileDir string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/profiles/",
+		func(w http.ResponseWriter, req *http.Request) {
+			name := strings.TrimPrefix(req.URL.Path, "/debug/profiles/")
+			// only serve profiles, never other files of the directory.
+			if profileDir == "" || name != filepath.Base(name) ||
+				!strings.HasSuffix(name, ".pprof") {
+				http.NotFound(w, req)
+				return
+			}
+			w.Header().Set("Content-Type", "application/octet-stream")
+			http.ServeFile(w, req, filepath.Join(profileDir, name))
+		},
+	)
+	return mux
+}
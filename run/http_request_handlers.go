@@ -4,21 +4,81 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 )
 
+// Form fields of multipart/form-data requests to the HTTPRunner.
+const (
+	// InputFormField is the form field holding the input.
+	InputFormField = "input"
+	// InitialSolutionFormField is the form field holding a solution to
+	// warm-start the algorithm with.
+	InitialSolutionFormField = "initial_solution"
+)
+
+// requestInput returns the input of the request and the options of the IOData
+// created for it. The input is either the body of the request or, for
+// multipart/form-data requests, the input form field. Multipart requests may
+// carry an initial solution in the initial_solution form field.
+func requestInput(req *http.Request) (io.Reader, []IODataOption, error) {
+	multipartReader, err := req.MultipartReader()
+	if errors.Is(err, http.ErrNotMultipart) {
+		return req.Body, nil, nil
+	}
+	if err != nil {
+		return nil, nil, httpError{status: http.StatusBadRequest, err: err}
+	}
+
+	var input io.Reader
+	var options []IODataOption
+	for {
+		part, err := multipartReader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, httpError{status: http.StatusBadRequest, err: err}
+		}
+		var buf bytes.Buffer
+		if _, err := buf.ReadFrom(part); err != nil {
+			return nil, nil, err
+		}
+		switch part.FormName() {
+		case InputFormField:
+			input = &buf
+		case InitialSolutionFormField:
+			options = append(options, WithInitialSolution(&buf))
+		}
+	}
+	if input == nil {
+		return nil, nil, httpError{
+			status: http.StatusBadRequest,
+			err:    fmt.Errorf("multipart request has no %s field", InputFormField),
+		}
+	}
+	return input, options, nil
+}
+
 // SyncHTTPRequestHandler allows the input and option to be sent as body and
 // query parameters. The output is written synchronously to the response writer.
+// Alternatively, the input and an initial solution can be sent as
+// multipart/form-data.
 func SyncHTTPRequestHandler(
 	w http.ResponseWriter, req *http.Request,
 ) (Callback, IOProducer[HTTPRunnerConfig], error) {
 	return nil,
-		func(_ context.Context, _ HTTPRunnerConfig) (IOData, error) {
+		func(_ context.Context, cfg HTTPRunnerConfig) (IOData, error) {
+			input, options, err := requestInput(req)
+			if err != nil {
+				return nil, err
+			}
 			return NewIOData(
-				req.Body,
+				input,
 				req.URL.Query(),
 				w,
+				append(options, inputLimits(cfg)...)...,
 			)
 		}, nil
 }
@@ -99,18 +159,23 @@ func (a asyncHTTPHandler) Handler(
 		return err
 	}
 
-	body, err := io.ReadAll(req.Body)
+	input, options, err := requestInput(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, err := io.ReadAll(input)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	return callbackFunc, func(
-		_ context.Context, _ HTTPRunnerConfig,
+		_ context.Context, cfg HTTPRunnerConfig,
 	) (IOData, error) {
 		return NewIOData(
 			bytes.NewReader(body),
 			req.URL.Query(),
 			buf,
+			append(options, inputLimits(cfg)...)...,
 		)
 	}, nil
 }
@@ -0,0 +1,170 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/itzg/go-flagsfiller"
+	"github.com/nextmv-io/sdk/run/decode"
+	"github.com/nextmv-io/sdk/run/encode"
+	"github.com/nextmv-io/sdk/run/validate"
+)
+
+// solvePath is the path prefix of the algorithms added to the HTTPRunner with
+// AddRoute. The algorithm named name is served at /solve/{name}.
+const solvePath = "/solve/"
+
+// Route is an algorithm served by the HTTPRunner next to its main algorithm,
+// see NewRoute and AddRoute.
+type Route struct {
+	name string
+	// build creates the solver of the route with the configuration of the
+	// HTTPRunner.
+	build func(HTTPRunnerConfig) (solver, error)
+	// endpoint describes the route in the OpenAPI document.
+	endpoint func(path string) (openAPIEndpoint, error)
+}
+
+// NewRoute creates a route serving the algorithm under the given name. Like
+// the main algorithm of the HTTPRunner, it decodes its input from JSON and
+// its options from the query parameters, and encodes its solutions as JSON.
+// The defaults of the options are taken from the default tags and from env
+// vars prefixed with the name of the route, e.g. SUM_FACTOR for the Factor
+// option of the route sum, so routes do not pick up the env vars of the main
+// algorithm. Flags only configure the main algorithm. The given runner options
+// customize the runner of the route.
+func NewRoute[Input, Option, Solution any](
+	name string,
+	algorithm Algorithm[Input, Option, Solution],
+	options ...RunnerOption[HTTPRunnerConfig, Input, Option, Solution],
+) Route {
+	return Route{
+		name: name,
+		build: func(runnerConfig HTTPRunnerConfig) (solver, error) {
+			_, option, err := parseFlags[Option, struct{}](
+				nil,
+				os.Environ(),
+				flagsfiller.CompositeRenamer(
+					flagsfiller.PrefixRenamer(name+"-"), envRenamer,
+				),
+			)
+			if err != nil {
+				return nil, err
+			}
+			runner, err := newGenericRunner[HTTPRunnerConfig](
+				runnerConfig,
+				option,
+				nil,
+				GenericDecoder[Input](decode.JSON()),
+				validate.JSON[Input](nil),
+				QueryParamDecoder[Option],
+				algorithm,
+				GenericEncoder[Solution, Option](encode.JSON()),
+			)
+			if err != nil {
+				return nil, err
+			}
+			for _, runnerOption := range options {
+				runnerOption(runner)
+			}
+			return runnerSolver[Input, Option, Solution]{runner: runner}, nil
+		},
+		endpoint: func(path string) (openAPIEndpoint, error) {
+			return newOpenAPIEndpoint[Input, Option, Solution](path, name)
+		},
+	}
+}
+
+// AddRoute adds an algorithm to the HTTPRunner. It is served at
+// /solve/{name} and shares the server, the authentication and the limits
+// with the main algorithm, which is served at all other paths.
+func AddRoute[Input, Option, Solution any](
+	route Route,
+) func(*httpRunner[Input, Option, Solution]) {
+	return func(r *httpRunner[Input, Option, Solution]) {
+		r.addRoute(route)
+	}
+}
+
+// solver runs an algorithm of the HTTPRunner, independent of its types.
+type solver interface {
+	// contentType returns the content type of the encoded solutions.
+	contentType() (string, error)
+	// run runs the algorithm on the IOData the producer creates.
+	run(ctx context.Context, producer IOProducer[HTTPRunnerConfig]) error
+}
+
+// runnerSolver is the solver of a runner.
+type runnerSolver[Input, Option, Solution any] struct {
+	runner Runner[HTTPRunnerConfig, Input, Option, Solution]
+}
+
+func (s runnerSolver[Input, Option, Solution]) contentType() (string, error) {
+	contentTyper, ok := s.runner.GetEncoder().(ContentTyper)
+	if !ok {
+		return "", errors.New("encoder does not implement ContentTyper")
+	}
+	return contentTyper.ContentType(), nil
+}
+
+func (s runnerSolver[Input, Option, Solution]) run(
+	ctx context.Context, producer IOProducer[HTTPRunnerConfig],
+) error {
+	// get a copy of the runner, so runs do not share the IOProducer.
+	runner := cloneRunner(s.runner)
+	runner.SetIOProducer(producer)
+	return runner.Run(ctx)
+}
+
+func (s runnerSolver[Input, Option, Solution]) closeTracer() error {
+	return closeTracer(s.runner)
+}
+
+// routedSolver is the solver and the OpenAPI description of a route.
+type routedSolver struct {
+	solver
+	endpoint func(path string) (openAPIEndpoint, error)
+}
+
+func (h *httpRunner[Input, Option, Solution]) addRoute(route Route) {
+	if route.name == "" || strings.Contains(route.name, "/") {
+		log.Fatal(fmt.Errorf("invalid route name %q", route.name))
+	}
+	if _, ok := h.routes[route.name]; ok {
+		log.Fatal(fmt.Errorf("route %q added twice", route.name))
+	}
+	s, err := route.build(h.Runner.RunnerConfig())
+	if err != nil {
+		log.Fatal(fmt.Errorf("route %q: %w", route.name, err))
+	}
+	if h.routes == nil {
+		h.routes = map[string]routedSolver{}
+	}
+	h.routes[route.name] = routedSolver{solver: s, endpoint: route.endpoint}
+}
+
+// route returns the solver serving the path. It reports false, if the path is
+// below solvePath, but no route has the name.
+func (h *httpRunner[Input, Option, Solution]) route(path string) (solver, bool) {
+	name, ok := strings.CutPrefix(path, solvePath)
+	if !ok {
+		return runnerSolver[Input, Option, Solution]{runner: h.Runner}, true
+	}
+	route, ok := h.routes[name]
+	return route.solver, ok
+}
+
+// routeNames returns the names of the routes in order.
+func (h *httpRunner[Input, Option, Solution]) routeNames() []string {
+	names := make([]string, 0, len(h.routes))
+	for name := range h.routes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -3,14 +3,19 @@ package run
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"os"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/nextmv-io/sdk/run/decode"
 	"github.com/nextmv-io/sdk/run/encode"
+	"github.com/nextmv-io/sdk/run/trace"
 	"github.com/nextmv-io/sdk/run/validate"
 )
 
@@ -73,6 +78,52 @@ func SetRunnerOption[Input, Option, Solution any](
 	}
 }
 
+// SetCache enables caching the results of sync requests. Identical requests,
+// i.e. requests with the same input and options, are answered with the cached
+// output instead of running the algorithm again. The cache holds up to size
+// results, each for the given time to live. A size of 0 disables the cache.
+func SetCache[Input, Option, Solution any](
+	size int, ttl time.Duration,
+) func(*httpRunner[Input, Option, Solution]) {
+	return func(r *httpRunner[Input, Option, Solution]) {
+		r.setCache(size, ttl)
+	}
+}
+
+// SetAuthenticator sets the authenticator requests need to pass. Requests
+// failing it are rejected with 401 Unauthorized. By default, requests are
+// authenticated with the API keys and client certificates configured, if any.
+func SetAuthenticator[Input, Option, Solution any](
+	authenticator Authenticator,
+) func(*httpRunner[Input, Option, Solution]) {
+	return func(r *httpRunner[Input, Option, Solution]) {
+		r.setAuthenticator(authenticator)
+	}
+}
+
+// SetRateLimit limits the requests of each client to rate requests per second
+// on average and burst requests at once. Requests exceeding the limit are
+// rejected with 429 Too Many Requests. A rate of 0 disables the limit.
+func SetRateLimit[Input, Option, Solution any](
+	rate float64, burst int,
+) func(*httpRunner[Input, Option, Solution]) {
+	return func(r *httpRunner[Input, Option, Solution]) {
+		r.setRateLimit(rate, burst)
+	}
+}
+
+// SetQueue sets the max number of requests waiting for a free slot when all
+// slots are taken, see SetMaxParallel. Waiting requests of different clients
+// are served round-robin. A size of 0 rejects requests when all slots are
+// taken.
+func SetQueue[Input, Option, Solution any](
+	size int,
+) func(*httpRunner[Input, Option, Solution]) {
+	return func(r *httpRunner[Input, Option, Solution]) {
+		r.scheduler.maxQueued = size
+	}
+}
+
 // SetHTTPServer sets the http server. Note that if you want to set the address
 // or the logger of the http server you are setting through this option and you
 // want to make use of SetAddr and SetLogger, you should pass them after passing
@@ -112,6 +163,16 @@ func NewHTTPRunner[Input, Option, Solution any](
 
 	runnerConfig := runner.Runner.RunnerConfig()
 	runner.maxParallel = make(chan struct{}, runnerConfig.Runner.HTTP.MaxParallel)
+	runner.scheduler = newScheduler(
+		runner.maxParallel, runnerConfig.Runner.HTTP.Queue,
+	)
+	if err := validateClientBy(runnerConfig.Runner.HTTP.Client); err != nil {
+		log.Fatal(err)
+	}
+	runner.setRateLimit(
+		runnerConfig.Runner.HTTP.RateLimit.Rate,
+		runnerConfig.Runner.HTTP.RateLimit.Burst,
+	)
 
 	// default http server
 	runner.httpServer = &http.Server{
@@ -124,10 +185,37 @@ func NewHTTPRunner[Input, Option, Solution any](
 	// default handler to IOProducer
 	runner.httpRequestHandler = SyncHTTPRequestHandler
 
+	runner.setCache(
+		runnerConfig.Runner.HTTP.Cache.Size,
+		runnerConfig.Runner.HTTP.Cache.TTL,
+	)
+
+	authenticator, err := configuredAuthenticator(runnerConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	runner.authenticator = authenticator
+
 	for _, option := range options {
 		option(runner)
 	}
 
+	runner.openAPI = sync.OnceValues(func() (openAPI, error) {
+		endpoint, err := newOpenAPIEndpoint[Input, Option, Solution]("/", "")
+		if err != nil {
+			return openAPI{}, err
+		}
+		endpoints := []openAPIEndpoint{endpoint}
+		for _, name := range runner.routeNames() {
+			endpoint, err := runner.routes[name].endpoint(solvePath + name)
+			if err != nil {
+				return openAPI{}, fmt.Errorf("route %q: %w", name, err)
+			}
+			endpoints = append(endpoints, endpoint)
+		}
+		return openAPIDocument(runner.authenticator != nil, endpoints...)
+	})
+
 	return runner
 }
 
@@ -136,6 +224,14 @@ type httpRunner[Input, Option, Solution any] struct {
 	httpServer         *http.Server
 	maxParallel        chan struct{}
 	httpRequestHandler HTTPRequestHandler
+	cache              *resultCache
+	authenticator      Authenticator
+	quotas             quotas
+	rateLimiter        *rateLimiter
+	scheduler          *scheduler
+	runs               runRegistry
+	routes             map[string]routedSolver
+	openAPI            func() (openAPI, error)
 }
 
 func (h *httpRunner[Input, Option, Solution]) setHTTPAddr(addr string) {
@@ -148,6 +244,13 @@ func (h *httpRunner[Input, Option, Solution]) setLogger(l *log.Logger) {
 
 func (h *httpRunner[Input, Option, Solution]) setMaxParallel(maxParallel int) {
 	h.maxParallel = make(chan struct{}, maxParallel)
+	h.scheduler.slots = h.maxParallel
+}
+
+func (h *httpRunner[Input, Option, Solution]) setRateLimit(
+	rate float64, burst int,
+) {
+	h.rateLimiter = newRateLimiter(rate, burst)
 }
 
 func (h *httpRunner[Input, Option, Solution]) ActiveRuns() int {
@@ -160,6 +263,21 @@ func (h *httpRunner[Input, Option, Solution]) setHTTPRequestHandler(
 	h.httpRequestHandler = f
 }
 
+func (h *httpRunner[Input, Option, Solution]) setCache(
+	size int, ttl time.Duration,
+) {
+	h.cache = nil
+	if size > 0 {
+		h.cache = newResultCache(size, ttl)
+	}
+}
+
+func (h *httpRunner[Input, Option, Solution]) setAuthenticator(
+	authenticator Authenticator,
+) {
+	h.authenticator = authenticator
+}
+
 func (h *httpRunner[Input, Option, Solution]) setHTTPServer(s *http.Server) {
 	h.httpServer = s
 }
@@ -172,8 +290,41 @@ func (h *httpRunner[Input, Option, Solution]) setRunnerOption(
 
 func (h *httpRunner[Input, Option, Solution]) Run(
 	_ context.Context,
-) error {
+) (retErr error) {
+	// spans of runs still active once the server stops are dropped.
+	defer func() {
+		err := h.closeTracers()
+		// the first error is more important
+		if retErr == nil {
+			retErr = err
+		}
+	}()
 	httpRunnerConfig := h.Runner.RunnerConfig()
+	if printed, err := printVersion(httpRunnerConfig); printed {
+		return err
+	}
+	if address := httpRunnerConfig.Runner.HTTP.Admin.Address; address != "" {
+		admin := &http.Server{
+			ReadHeaderTimeout: httpRunnerConfig.Runner.HTTP.ReadHeaderTimeout,
+			Addr:              address,
+			ErrorLog:          h.httpServer.ErrorLog,
+			Handler:           adminHandler(httpRunnerConfig.Runner.Profile.Dir),
+		}
+		go func() {
+			err := admin.ListenAndServe()
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				admin.ErrorLog.Println(err)
+			}
+		}()
+		defer admin.Close()
+	}
+	if caPath := httpRunnerConfig.Runner.HTTP.Auth.ClientCA; caPath != "" {
+		tlsConfig, err := clientTLSConfig(h.httpServer.TLSConfig, caPath)
+		if err != nil {
+			return err
+		}
+		h.httpServer.TLSConfig = tlsConfig
+	}
 	if httpRunnerConfig.Runner.HTTP.Certificate != "" ||
 		httpRunnerConfig.Runner.HTTP.Key != "" {
 		return h.httpServer.ListenAndServeTLS(
@@ -184,40 +335,164 @@ func (h *httpRunner[Input, Option, Solution]) Run(
 	return h.httpServer.ListenAndServe()
 }
 
+// closeTracers closes the tracers of the main algorithm and of the routes.
+func (h *httpRunner[Input, Option, Solution]) closeTracers() error {
+	errs := []error{closeTracer(h.Runner)}
+	for _, route := range h.routes {
+		errs = append(errs, closeTracer(route.solver))
+	}
+	return errors.Join(errs...)
+}
+
 // ServeHTTP implements the http.Handler interface.
 func (h *httpRunner[Input, Option, Solution]) ServeHTTP(
 	w http.ResponseWriter, req *http.Request,
 ) {
-	select {
-	case h.maxParallel <- struct{}{}:
-	default:
-		// No free slot, so we immediately return an error.
-		http.Error(w, "max number of parallel requests exceeded",
+	// limit the size of the body as received.
+	if size, _ := h.Runner.RunnerConfig().InputLimits(); size > 0 {
+		req.Body = http.MaxBytesReader(w, req.Body, size)
+	}
+	// the API documentation and the health are public.
+	switch req.URL.Path {
+	case openAPIPath:
+		if err := serveOpenAPI(w, req, h.openAPI); err != nil {
+			handleError(h.httpServer.ErrorLog, false, err, w)
+		}
+		return
+	case healthPath:
+		err := serveHealth(w, req, h.ActiveRuns(), h.routeNames())
+		if err != nil {
+			handleError(h.httpServer.ErrorLog, false, err, w)
+		}
+		return
+	}
+	principal := Principal{}
+	if h.authenticator != nil {
+		var err error
+		principal, err = h.authenticator.Authenticate(req)
+		if err != nil {
+			handleError(h.httpServer.ErrorLog, false,
+				httpError{status: http.StatusUnauthorized, err: err}, w)
+			return
+		}
+	}
+
+	// the version reveals the revision and the dependencies of the build.
+	if req.URL.Path == versionPath {
+		if err := serveVersion(w, req); err != nil {
+			handleError(h.httpServer.ErrorLog, false, err, w)
+		}
+		return
+	}
+	if requestID, action, ok := runEndpoint(req); ok {
+		h.runs.serve(w, req, requestID, action, principal)
+		return
+	}
+	solver, ok := h.route(req.URL.Path)
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+
+	client := clientOf(req, principal, h.Runner.RunnerConfig().Runner.HTTP.Client)
+	if h.rateLimiter != nil {
+		if ok, wait := h.rateLimiter.allow(client); !ok {
+			seconds := int(math.Ceil(wait.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+	}
+	if !h.quotas.acquire(principal) {
+		http.Error(w, "max number of parallel requests of client exceeded",
 			http.StatusTooManyRequests)
 		return
 	}
+	// wait for a free slot, if the queue is enabled.
+	if err := h.scheduler.acquire(req.Context(), client); err != nil {
+		h.quotas.release(principal)
+		if errors.Is(err, errQueueFull) {
+			// No free slot, so we immediately return an error.
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+		}
+		return
+	}
 
 	// control mechanism to let the request by run async or not.
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
-		defer func() { <-h.maxParallel }()
+		defer func() {
+			h.scheduler.release()
+			h.quotas.release(principal)
+		}()
+		// take the profiles requested for this run out of the query.
+		profiler, err := popRunProfiles(
+			req, h.Runner.RunnerConfig().Runner.Profile.Dir,
+		)
+		if err != nil {
+			handleError(h.httpServer.ErrorLog, false, err, w)
+			wg.Done()
+			return
+		}
+		// results of profiled runs are never served from the cache.
+		cacheable := h.cache != nil && profiler == (runProfiler{})
+		key := ""
+		if cacheable {
+			key, err = cacheKey(req)
+			if err != nil {
+				handleError(h.httpServer.ErrorLog, false, err, w)
+				wg.Done()
+				return
+			}
+		}
+		// compress the response, if the client accepts it.
+		var gzipWriter *gzipResponseWriter
+		responseWriter := w
+		if acceptsGzip(req) {
+			gzipWriter = newGzipResponseWriter(w)
+			responseWriter = gzipWriter
+		}
+		// record the response, so it can be cached.
+		var recorder *recordingResponseWriter
+		handlerWriter := responseWriter
+		if cacheable {
+			recorder = &recordingResponseWriter{ResponseWriter: responseWriter}
+			handlerWriter = recorder
+		}
 		// configure how to turn the request and response into an IOProducer.
-		callbackFunc, producer, err := h.httpRequestHandler(w, req)
+		callbackFunc, producer, err := h.httpRequestHandler(handlerWriter, req)
 		async := callbackFunc != nil
 		if err != nil {
 			handleError(h.httpServer.ErrorLog, async, err, w)
 			wg.Done()
 			return
 		}
+		// only sync responses are cached.
+		cacheable = cacheable && !async
+		if cacheable {
+			if result, ok := h.cache.get(key); ok && !bypassesCache(req) {
+				w.Header().Set("Content-Type", result.contentType)
+				w.Header().Set(CacheHeader, cacheHit)
+				_, err = responseWriter.Write(result.body)
+				if err == nil && gzipWriter != nil {
+					err = gzipWriter.Close()
+				}
+				if err != nil {
+					handleError(h.httpServer.ErrorLog, async, err, w)
+				}
+				wg.Done()
+				return
+			}
+			w.Header().Set(CacheHeader, cacheMiss)
+		}
 		// generate a new requestID
 		requestID := uuid.New().String()
 
 		// get content type from the encoder
-		contentTyper, ok := h.Runner.GetEncoder().(ContentTyper)
-		if !ok {
-			handleError(h.httpServer.ErrorLog, async,
-				errors.New("encoder does not implement ContentTyper"), w)
+		contentType, err := solver.contentType()
+		if err != nil {
+			handleError(h.httpServer.ErrorLog, async, err, w)
 			wg.Done()
 			return
 		}
@@ -232,25 +507,56 @@ func (h *httpRunner[Input, Option, Solution]) ServeHTTP(
 			}
 			wg.Done()
 		} else {
-			w.Header().Add("Content-Type", contentTyper.ContentType())
+			w.Header().Add("Content-Type", contentType)
+			w.Header().Set("request_id", requestID)
 			defer wg.Done()
+			if gzipWriter != nil {
+				// end the gzip stream before the response is done.
+				defer func() {
+					if err := gzipWriter.Close(); err != nil {
+						h.httpServer.ErrorLog.Println(err)
+					}
+				}()
+			}
 		}
 		if err != nil {
 			handleError(h.httpServer.ErrorLog, async, err, w)
 			return
 		}
-		// get a copy of the genericRunner set the IOProducer and run it.
-		genericRunner := h.Runner
-		genericRunner.SetIOProducer(producer)
-		err = genericRunner.Run(context.Background())
+		// continue the trace of the caller, if it propagated one.
+		ctx := trace.Extract(context.Background(), req.Header)
+		// track the run, so its progress can be followed and it can be
+		// cancelled.
+		ctx, cancel := context.WithCancelCause(ctx)
+		progress := newProgressTracker(nil, 0)
+		ctx = context.WithValue(ctx, progressKey{}, progress)
+		ctx, termination := withTerminationRecord(ctx)
+		h.runs.track(requestID, principal, cancel, progress)
+		defer func() {
+			h.runs.untrack(requestID)
+			cancel(nil)
+		}()
+		stopProfiler, err := profiler.start(requestID)
+		if err != nil {
+			handleError(h.httpServer.ErrorLog, async, err, w)
+			return
+		}
+		err = errors.Join(solver.run(ctx, producer), stopProfiler())
 		if err != nil {
 			handleError(h.httpServer.ErrorLog, async, err, w)
 			return
 		}
 
+		// results of runs stopped early, e.g. because they were cancelled or
+		// hit a limit, are not the results of the request, so they are not
+		// cached.
+		if cacheable && !termination.terminated && req.Context().Err() == nil {
+			h.cache.put(key, contentType, recorder.body.Bytes())
+		}
+
 		// if the request is async, call the callbackFunc.
 		if async {
-			err = callbackFunc(requestID, contentTyper.ContentType())
+			err = callbackFunc(requestID, contentType)
 			if err != nil {
 				handleError(h.httpServer.ErrorLog, async, err, w)
 				return
@@ -265,6 +571,62 @@ func handleError(log *log.Logger,
 ) {
 	log.Println(err)
 	if !async {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		status := http.StatusInternalServerError
+		var httpErr httpError
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.Is(err, ErrInputTooLarge), errors.As(err, &maxBytesErr):
+			status = http.StatusRequestEntityTooLarge
+		case errors.As(err, &httpErr):
+			status = httpErr.status
+		}
+		http.Error(w, err.Error(), status)
 	}
 }
+
+// configuredAuthenticator returns the authenticator of the API keys and client
+// certificates configured. It returns nil, if neither is configured.
+func configuredAuthenticator(
+	runnerConfig HTTPRunnerConfig,
+) (Authenticator, error) {
+	auth := runnerConfig.Runner.HTTP.Auth
+	if auth.ClientCA != "" && (runnerConfig.Runner.HTTP.Certificate == "" ||
+		runnerConfig.Runner.HTTP.Key == "") {
+		// without TLS, no client sends a certificate.
+		return nil, errors.New("runner.http.auth.client_ca requires " +
+			"runner.http.certificate and runner.http.key")
+	}
+	var keys []APIKey
+	if auth.Keys != "" {
+		var err error
+		if keys, err = ReadAPIKeys(auth.Keys); err != nil {
+			return nil, err
+		}
+	}
+	var authenticators []Authenticator
+	if auth.ClientCA != "" {
+		authenticators = append(authenticators, ClientCertificates(keys))
+	}
+	if auth.Keys != "" {
+		authenticators = append(authenticators,
+			APIKeys(keys), HMACSignatures(keys, auth.MaxSkew))
+	}
+	if len(authenticators) == 0 {
+		return nil, nil
+	}
+	return AnyOf(authenticators...), nil
+}
+
+// httpError is an error that is reported with the given HTTP status code.
+type httpError struct {
+	status int
+	err    error
+}
+
+func (e httpError) Error() string {
+	return e.err.Error()
+}
+
+func (e httpError) Unwrap() error {
+	return e.err
+}
@@ -8,9 +8,13 @@ import (
 // HTTPRunnerConfig defines the configuration of the HTTPRunner.
 type HTTPRunnerConfig struct {
 	Runner struct {
-		Log    *log.Logger
-		Output struct {
-			Solutions string `default:"last" usage:"Return all or last solution"`
+		Seed    int64 `usage:"The seed of the random number generators of a run, generated if 0"`
+		Version bool  `usage:"Print the build information as JSON and exit"`
+		Log     *log.Logger
+		Output  struct {
+			Solutions  string `default:"last" usage:"Return all or last solution"`
+			Validation string `default:"off" usage:"{off, warn, fail}, whether solutions are validated against the output schema and invalid ones are logged or fail the run"`
+			Schema     string `usage:"The file path of the JSON schema solutions are validated against, generated from the solution type if empty"`
 		}
 		HTTP struct {
 			Address           string        `default:":9000" usage:"The host address"`
@@ -18,11 +22,87 @@ type HTTPRunnerConfig struct {
 			Key               string        `usage:"The key file path"`
 			ReadHeaderTimeout time.Duration `default:"60s" usage:"The maximum duration for reading the request headers"`
 			MaxParallel       int           `default:"1" usage:"The max number of requests"`
+			Queue             int           `usage:"The max number of requests waiting for a free slot, served round-robin across clients (0 rejects requests when all slots are taken)"`
+			Client            string        `default:"ip" usage:"How clients are identified for rate limits and scheduling {ip, key, header:<name>}"`
+			RateLimit         struct {
+				Rate  float64 `usage:"The number of requests per second a client may send on average (0 means no limit)"`
+				Burst int     `default:"1" usage:"The number of requests a client may send at once"`
+			}
+			Cache struct {
+				Size int           `usage:"The max number of results cached for identical sync requests, 0 disables the cache"`
+				TTL  time.Duration `default:"1h" usage:"The duration results are cached for, 0 keeps them until evicted"`
+			}
+			Auth struct {
+				Keys     string        `usage:"The file path of the API keys clients authenticate with, a JSON array of objects with id, key and max_parallel"`
+				ClientCA string        `flag:"runner.http.auth.client_ca" usage:"The file path of the certificate authorities client certificates are verified with"`
+				MaxSkew  time.Duration `default:"5m" usage:"The max deviation of the timestamp of signed requests from the current time"`
+			}
+			Admin struct {
+				Address string `usage:"The host address of the admin server exposing pprof endpoints, disabled if empty"`
+			}
+		}
+		Profile struct {
+			Dir string `usage:"The directory profiles requested per run via the profile query parameter are stored in"`
+		}
+		Tracing struct {
+			Path string `usage:"The file path spans are written to as JSON lines"`
+		}
+		Limits struct {
+			Memory    int64 `usage:"The soft memory limit of the process in bytes, exceeding it terminates the active runs (0 means no limit)"`
+			Solutions int   `usage:"The max number of solutions accepted from the algorithm (0 means no limit)"`
+			Input     struct {
+				Size         int64 `usage:"The max size of the input in bytes, as received (0 means no limit)"`
+				Decompressed int64 `usage:"The max size of the input in bytes, after decompressing it (0 means no limit)"`
+			}
 		}
 	}
 }
 
+// MemoryLimit returns the soft memory limit in bytes.
+func (c HTTPRunnerConfig) MemoryLimit() int64 {
+	return c.Runner.Limits.Memory
+}
+
+// MaxSolutions returns the max number of solutions accepted from the
+// algorithm.
+func (c HTTPRunnerConfig) MaxSolutions() int {
+	return c.Runner.Limits.Solutions
+}
+
+// InputLimits returns the max size of the input in bytes, as received and
+// after decompressing it.
+func (c HTTPRunnerConfig) InputLimits() (size, decompressed int64) {
+	return c.Runner.Limits.Input.Size, c.Runner.Limits.Input.Decompressed
+}
+
+// TracingPath returns the tracing path.
+func (c HTTPRunnerConfig) TracingPath() string {
+	return c.Runner.Tracing.Path
+}
+
 // Solutions returns the configured solutions.
 func (c HTTPRunnerConfig) Solutions() (Solutions, error) {
 	return ParseSolutions(c.Runner.Output.Solutions)
 }
+
+// OutputValidation returns how solutions are validated.
+func (c HTTPRunnerConfig) OutputValidation() (Validation, error) {
+	return ParseValidation(c.Runner.Output.Validation)
+}
+
+// OutputSchemaPath returns the path of the schema solutions are validated
+// against.
+func (c HTTPRunnerConfig) OutputSchemaPath() string {
+	return c.Runner.Output.Schema
+}
+
+// PrintVersion returns whether to print the build information instead of
+// running.
+func (c HTTPRunnerConfig) PrintVersion() bool {
+	return c.Runner.Version
+}
+
+// Seed returns the seed of the random number generators of a run.
+func (c HTTPRunnerConfig) Seed() int64 {
+	return c.Runner.Seed
+}
@@ -0,0 +1,182 @@
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// runsPath is the path prefix of the endpoints managing the active runs of
+// the HTTPRunner:
+//
+//   - GET /runs/{request_id} returns the status of the run with its progress,
+//     see Report.
+//   - GET /runs/{request_id}/progress streams the progress of the run as
+//     server-sent events, until the run is done.
+//   - POST /runs/{request_id}/cancel cancels the run. The algorithm context is
+//     cancelled and the best solution found so far is delivered, marked as
+//     cancelled in its statistics.
+const runsPath = "/runs/"
+
+// Actions of the endpoints of the runs.
+const (
+	runStatusAction   = ""
+	runProgressAction = "progress"
+	runCancelAction   = "cancel"
+)
+
+// runEndpoint returns the request id of the run and the action requested, if
+// the request is made to one of the endpoints of the runs.
+func runEndpoint(req *http.Request) (id, action string, ok bool) {
+	rest, ok := strings.CutPrefix(req.URL.Path, runsPath)
+	if !ok {
+		return "", "", false
+	}
+	id, action, _ = strings.Cut(rest, "/")
+	switch action {
+	case runStatusAction, runProgressAction, runCancelAction:
+		return id, action, id != ""
+	}
+	return "", "", false
+}
+
+// runStatus is the response of the status endpoint of a run.
+type runStatus struct {
+	RequestID string `json:"request_id"`
+	// Status is always running, as only active runs are tracked.
+	Status   string    `json:"status"`
+	Progress *Progress `json:"progress,omitempty"`
+}
+
+func newRunStatus(requestID string, run trackedRun) runStatus {
+	status := runStatus{RequestID: requestID, Status: "running"}
+	if progress, ok := run.progress.latest(); ok {
+		status.Progress = &progress
+	}
+	return status
+}
+
+// runRegistry tracks the cancel functions of the active runs by request id.
+type runRegistry struct {
+	mutex sync.Mutex
+	runs  map[string]trackedRun
+}
+
+type trackedRun struct {
+	// principal is the client that started the run, only it may access it.
+	principal Principal
+	cancel    context.CancelCauseFunc
+	progress  *progressTracker
+}
+
+func (r *runRegistry) track(
+	requestID string,
+	principal Principal,
+	cancel context.CancelCauseFunc,
+	progress *progressTracker,
+) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.runs == nil {
+		r.runs = map[string]trackedRun{}
+	}
+	r.runs[requestID] = trackedRun{
+		principal: principal,
+		cancel:    cancel,
+		progress:  progress,
+	}
+}
+
+// untrack stops tracking the run and ends the streams of its progress.
+func (r *runRegistry) untrack(requestID string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if run, ok := r.runs[requestID]; ok {
+		run.progress.close()
+		delete(r.runs, requestID)
+	}
+}
+
+// get returns the run with the given request id, if the principal started it.
+func (r *runRegistry) get(
+	requestID string, principal Principal,
+) (trackedRun, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	run, ok := r.runs[requestID]
+	if !ok || run.principal.ID != principal.ID {
+		return trackedRun{}, false
+	}
+	return run, true
+}
+
+// serve handles a request to the endpoints of the runs.
+func (r *runRegistry) serve(
+	w http.ResponseWriter, req *http.Request,
+	requestID, action string, principal Principal,
+) {
+	method := http.MethodGet
+	if action == runCancelAction {
+		method = http.MethodPost
+	}
+	if req.Method != method {
+		w.Header().Set("Allow", method)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	run, ok := r.get(requestID, principal)
+	if !ok {
+		http.Error(w, "run "+requestID+" not found", http.StatusNotFound)
+		return
+	}
+	switch action {
+	case runCancelAction:
+		run.cancel(TerminationCancelled)
+		w.WriteHeader(http.StatusAccepted)
+	case runProgressAction:
+		streamProgress(w, req, run.progress)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(newRunStatus(requestID, run))
+	}
+}
+
+// streamProgress writes the progress of a run as server-sent events of type
+// progress, until the run or the request is done. A final event of type done
+// marks the end of the run.
+func streamProgress(
+	w http.ResponseWriter, req *http.Request, tracker *progressTracker,
+) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	updates, unsubscribe := tracker.subscribe()
+	defer unsubscribe()
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case progress, ok := <-updates:
+			if !ok {
+				fmt.Fprint(w, "event: done\ndata: {}\n\n")
+				flusher.Flush()
+				return
+			}
+			data, err := json.Marshal(progress)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "event: progress\ndata: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// clientOf returns the identifier of the client that sent the request, used to
+// rate limit and schedule its requests. The by argument is one of "ip", "key",
+// identifying clients by the authenticated principal and falling back to the
+// remote IP, or "header:<name>", identifying clients by the value of the
+// header.
+func clientOf(req *http.Request, principal Principal, by string) string {
+	switch {
+	case by == "key" && principal.ID != "":
+		return "key:" + principal.ID
+	case strings.HasPrefix(by, "header:"):
+		name := strings.TrimPrefix(by, "header:")
+		return "header:" + req.Header.Get(name)
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// validateClientBy returns an error, if by is not a supported way to identify
+// clients, see clientOf.
+func validateClientBy(by string) error {
+	if by == "ip" || by == "key" ||
+		strings.HasPrefix(by, "header:") && len(by) > len("header:") {
+		return nil
+	}
+	return fmt.Errorf(
+		"unknown client identification %q, use ip, key or header:<name>", by,
+	)
+}
+
+// rateLimiter limits the rate of requests per client with token buckets. Each
+// client may send burst requests at once, and rate requests per second on
+// average.
+type rateLimiter struct {
+	mutex   sync.Mutex
+	rate    float64
+	burst   float64
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// maxIdleBuckets is the number of buckets above which the buckets of clients
+// that did not send requests for a while are dropped.
+const maxIdleBuckets = 1024
+
+// newRateLimiter creates a rate limiter. It returns nil, if rate is not
+// positive, i.e. requests are not rate limited.
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	return &rateLimiter{
+		rate:    rate,
+		burst:   math.Max(float64(burst), 1),
+		buckets: map[string]*bucket{},
+	}
+}
+
+// allow takes a token of the client. If there is none, it returns false and
+// the duration until the next token is available.
+func (l *rateLimiter) allow(client string) (bool, time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	now := time.Now()
+	b, ok := l.buckets[client]
+	if !ok {
+		if len(l.buckets) >= maxIdleBuckets {
+			l.dropFullBuckets(now)
+		}
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[client] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens < 1 {
+		wait := (1 - b.tokens) / l.rate
+		return false, time.Duration(wait * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// dropFullBuckets drops the buckets that are refilled completely, as they are
+// equivalent to new ones.
+func (l *rateLimiter) dropFullBuckets(now time.Time) {
+	for client, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, client)
+		}
+	}
+}
+
+// errQueueFull is returned when a request can neither take a free slot nor
+// wait for one.
+var errQueueFull = errors.New("max number of parallel requests exceeded")
+
+// scheduler hands out the slots of the runner. Requests that find all slots
+// taken wait in a queue per client. Freed slots are handed to the clients
+// round-robin, so that one client sending many requests does not starve the
+// others.
+type scheduler struct {
+	mutex     sync.Mutex
+	slots     chan struct{}
+	maxQueued int
+	queued    int
+	// queues holds the waiting requests per client, clients holds the clients
+	// with waiting requests in the order they are served.
+	queues  map[string][]chan struct{}
+	clients []string
+}
+
+func newScheduler(slots chan struct{}, maxQueued int) *scheduler {
+	return &scheduler{
+		slots:     slots,
+		maxQueued: maxQueued,
+		queues:    map[string][]chan struct{}{},
+	}
+}
+
+// acquire takes a slot for the client, waiting for one if all are taken. It
+// returns errQueueFull if the queue is full and the error of the context, if
+// it is done before a slot is free. Taken slots need to be released.
+func (s *scheduler) acquire(ctx context.Context, client string) error {
+	s.mutex.Lock()
+	if s.queued == 0 {
+		select {
+		case s.slots <- struct{}{}:
+			s.mutex.Unlock()
+			return nil
+		default:
+		}
+	}
+	if s.queued >= s.maxQueued {
+		s.mutex.Unlock()
+		return errQueueFull
+	}
+	ready := make(chan struct{})
+	if len(s.queues[client]) == 0 {
+		s.clients = append(s.clients, client)
+	}
+	s.queues[client] = append(s.queues[client], ready)
+	s.queued++
+	s.mutex.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		select {
+		case <-ready:
+			// the slot was handed over in the meantime.
+			s.handOver()
+		default:
+			s.remove(client, ready)
+		}
+		return ctx.Err()
+	}
+}
+
+// release frees a slot, handing it to the next waiting request, if any.
+func (s *scheduler) release() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.handOver()
+}
+
+// handOver hands the slot of the caller to the first waiting request of the
+// next client. If no request is waiting, the slot is freed. The mutex must be
+// held.
+func (s *scheduler) handOver() {
+	if s.queued == 0 {
+		<-s.slots
+		return
+	}
+	client := s.clients[0]
+	queue := s.queues[client]
+	ready := queue[0]
+	s.queued--
+	if len(queue) == 1 {
+		delete(s.queues, client)
+		s.clients = s.clients[1:]
+	} else {
+		s.queues[client] = queue[1:]
+		// the client gets its next turn after all the others.
+		s.clients = append(s.clients[1:], client)
+	}
+	close(ready)
+}
+
+// remove removes a waiting request from the queue of the client. The mutex
+// must be held.
+func (s *scheduler) remove(client string, ready chan struct{}) {
+	queue := s.queues[client]
+	for i, r := range queue {
+		if r != ready {
+			continue
+		}
+		s.queued--
+		if len(queue) > 1 {
+			s.queues[client] = append(queue[:i:i], queue[i+1:]...)
+			return
+		}
+		delete(s.queues, client)
+		for j, c := range s.clients {
+			if c == client {
+				s.clients = append(s.clients[:j:j], s.clients[j+1:]...)
+				break
+			}
+		}
+		return
+	}
+}
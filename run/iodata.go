@@ -4,7 +4,11 @@ import (
 	"bufio"
 	"bytes"
 	"compress/gzip"
+	"errors"
+	"fmt"
 	"io"
+
+	"github.com/klauspost/compress/zstd"
 )
 
 // IOData describes the data that is used in the IOProducer. The input is the
@@ -16,17 +20,78 @@ type IOData interface {
 	Writer() any
 }
 
-// NewIOData creates a new IOData.
-func NewIOData(input any, option any, writer any) (data IOData, err error) {
+// InitialSolutioner is the interface an IOData can implement to provide the
+// source of a solution to warm-start the algorithm with.
+type InitialSolutioner interface {
+	InitialSolution() any
+}
+
+// IODataOption configures an IOData.
+type IODataOption func(*ioData)
+
+// WithInitialSolution sets the source of a solution to warm-start the
+// algorithm with. It is decoded with the solution decoder of the runner.
+func WithInitialSolution(initialSolution any) IODataOption {
+	return func(d *ioData) { d.initialSolution = initialSolution }
+}
+
+// ErrInputTooLarge is returned when an input exceeds its size limits.
+var ErrInputTooLarge = errors.New("input too large")
+
+// WithInputLimits limits the size of inputs and initial solutions given as
+// io.Reader. The compressed limit applies to the bytes as they are read, the
+// decompressed limit to the data after decompressing it. This protects against
+// decompression bombs. A limit of 0 disables it.
+func WithInputLimits(compressed, decompressed int64) IODataOption {
+	return func(d *ioData) {
+		d.maxCompressed = compressed
+		d.maxDecompressed = decompressed
+	}
+}
+
+// NewIOData creates a new IOData. Inputs and initial solutions given as
+// io.Reader are read into a buffer, decompressing gzipped or zstd compressed
+// data, and closed.
+func NewIOData(
+	input any, option any, writer any, options ...IODataOption,
+) (data IOData, err error) {
+	d := ioData{
+		input:  input,
+		option: option,
+		writer: writer,
+	}
+	for _, option := range options {
+		option(&d)
+	}
+
+	if reader, ok := d.initialSolution.(io.Reader); ok {
+		buf, err := d.buffer(reader)
+		if err != nil {
+			return ioData{}, err
+		}
+		d.initialSolution = bytes.NewReader(buf.Bytes())
+	}
+
 	reader, ok := input.(io.Reader)
 	if !ok {
-		return ioData{
-			input:  input,
-			option: option,
-			writer: writer,
-		}, nil
+		return d, nil
+	}
+
+	d.buf, err = d.buffer(reader)
+	if err != nil {
+		return ioData{}, err
 	}
+	return d, nil
+}
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
 
+// buffer reads the reader into a buffer and closes it, if it is an io.Closer.
+// Gzipped and zstd compressed data is decompressed.
+func (d ioData) buffer(reader io.Reader) (buf *bytes.Buffer, err error) {
 	if closer, ok := reader.(io.Closer); ok {
 		defer func() {
 			tempErr := closer.Close()
@@ -36,48 +101,106 @@ func NewIOData(input any, option any, writer any) (data IOData, err error) {
 			}
 		}()
 	}
+	reader = limit(reader, d.maxCompressed, "input")
 
-	// Convert to buffered reader and read magic bytes
+	// Convert to buffered reader and read magic bytes. Errors resurface when
+	// reading the data.
 	bufferedReader := bufio.NewReader(reader)
-	testBytes, err := bufferedReader.Peek(2)
+	testBytes, _ := bufferedReader.Peek(len(zstdMagic))
 
-	// Test for gzip magic bytes and use corresponding reader, if given
-	if err == nil && testBytes[0] == 31 && testBytes[1] == 139 {
+	// Test for magic bytes and use corresponding reader, if given
+	switch {
+	case bytes.HasPrefix(testBytes, gzipMagic):
 		var gzipReader *gzip.Reader
 		if gzipReader, err = gzip.NewReader(bufferedReader); err != nil {
-			return ioData{}, err
+			return nil, err
+		}
+		reader = limit(gzipReader, d.maxDecompressed, "decompressed input")
+	case bytes.HasPrefix(testBytes, zstdMagic):
+		zstdOptions := []zstd.DOption{zstd.WithDecoderConcurrency(1)}
+		if d.maxDecompressed > 0 {
+			// bound the window the decoder allocates, too. Windows are
+			// rounded up to powers of two.
+			zstdOptions = append(zstdOptions, zstd.WithDecoderMaxWindow(
+				max(2*uint64(d.maxDecompressed), zstd.MinWindowSize),
+			))
 		}
-		reader = gzipReader
-	} else {
-		// Default case: assume text input
-		reader = bufferedReader
+		zstdReader, err := zstd.NewReader(bufferedReader, zstdOptions...)
+		if err != nil {
+			return nil, err
+		}
+		defer zstdReader.Close()
+		reader = limit(zstdReader, d.maxDecompressed, "decompressed input")
+	default:
+		// Default case: assume text input, which is not compressed, so the
+		// decompressed limit applies as well.
+		reader = limit(bufferedReader, d.maxDecompressed, "input")
 	}
 
 	// copy input to buffer
-	buf := &bytes.Buffer{}
+	buf = &bytes.Buffer{}
 	_, err = buf.ReadFrom(reader)
+	if errors.Is(err, zstd.ErrWindowSizeExceeded) {
+		err = fmt.Errorf(
+			"%w: decompressed input exceeds %d bytes",
+			ErrInputTooLarge, d.maxDecompressed,
+		)
+	}
 	if err != nil {
-		return ioData{}, err
+		return nil, err
 	}
+	return buf, nil
+}
 
-	return ioData{
-		input:  input,
-		option: option,
-		writer: writer,
-		buf:    buf,
-	}, nil
+// limit returns a reader failing with ErrInputTooLarge once more than
+// limitBytes bytes are read. A limitBytes of 0 disables the limit.
+func limit(reader io.Reader, limitBytes int64, name string) io.Reader {
+	if limitBytes <= 0 {
+		return reader
+	}
+	return &limitedReader{
+		reader:     reader,
+		remaining:  limitBytes,
+		limitBytes: limitBytes,
+		name:       name,
+	}
+}
+
+type limitedReader struct {
+	reader     io.Reader
+	remaining  int64
+	limitBytes int64
+	name       string
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	// read one byte more than allowed to detect exceeding the limit.
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return n, fmt.Errorf(
+			"%w: %s exceeds %d bytes", ErrInputTooLarge, r.name, r.limitBytes,
+		)
+	}
+	return n, err
 }
 
 type ioData struct {
-	input  any
-	option any
-	writer any
-	buf    *bytes.Buffer
+	input           any
+	option          any
+	writer          any
+	initialSolution any
+	maxCompressed   int64
+	maxDecompressed int64
+	buf             *bytes.Buffer
 }
 
 func (d ioData) Input() (input any) {
 	// buffer was filled so use that instead of the original reader
-	if len(d.buf.Bytes()) > 0 {
+	if d.buf != nil && d.buf.Len() > 0 {
 		return bytes.NewReader(d.buf.Bytes())
 	}
 	return d.input
@@ -90,3 +213,7 @@ func (d ioData) Option() any {
 func (d ioData) Writer() any {
 	return d.writer
 }
+
+func (d ioData) InitialSolution() any {
+	return d.initialSolution
+}
@@ -0,0 +1,66 @@
+package run
+
+import (
+	"log"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// leakGracePeriod is the time goroutines are given to exit after the algorithm
+// returned before they are reported as leaked.
+const leakGracePeriod = 100 * time.Millisecond
+
+// goroutines returns the stack traces of all running goroutines keyed by their
+// ID.
+func goroutines() map[string]string {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	stacks := map[string]string{}
+	for _, stack := range strings.Split(string(buf), "\n\n") {
+		// every stack starts with a header like "goroutine 7 [running]:"
+		id, _, _ := strings.Cut(strings.TrimPrefix(stack, "goroutine "), " ")
+		stacks[id] = stack
+	}
+	return stacks
+}
+
+// leakedGoroutines returns the stack traces of the goroutines that are running
+// now but were not running before.
+func leakedGoroutines(before map[string]string) []string {
+	var leaked []string
+	for id, stack := range goroutines() {
+		if _, ok := before[id]; !ok {
+			leaked = append(leaked, stack)
+		}
+	}
+	sort.Strings(leaked)
+	return leaked
+}
+
+// reportLeakedGoroutines logs the goroutines started since the given snapshot
+// that are still running after a grace period.
+func reportLeakedGoroutines(before map[string]string) {
+	deadline := time.Now().Add(leakGracePeriod)
+	leaked := leakedGoroutines(before)
+	for len(leaked) > 0 && time.Now().Before(deadline) {
+		time.Sleep(leakGracePeriod / 10)
+		leaked = leakedGoroutines(before)
+	}
+	if len(leaked) == 0 {
+		return
+	}
+	log.Printf(
+		"algorithm leaked %d goroutine(s) still running after it returned:\n\n%s",
+		len(leaked), strings.Join(leaked, "\n\n"),
+	)
+}
@@ -0,0 +1,164 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/nextmv-io/sdk/run/schema"
+	"github.com/nextmv-io/sdk/run/statistics"
+	"github.com/nextmv-io/sdk/run/storage"
+)
+
+// ManifestFile is the name of the manifest listing the solution files written
+// with the Split output format.
+const ManifestFile = "manifest.json"
+
+// Manifest lists the solution files written with the Split output format.
+type Manifest struct {
+	Solutions []ManifestEntry `json:"solutions"`
+}
+
+// ManifestEntry describes a solution file written with the Split output
+// format.
+type ManifestEntry struct {
+	// File is the name of the file in the output directory.
+	File string `json:"file"`
+	// Time is when the solution was received from the algorithm.
+	Time time.Time `json:"time"`
+	// Objective is the value of the solution, if it is a schema.Output with
+	// a result value in its statistics.
+	Objective *statistics.Float64 `json:"objective,omitempty"`
+}
+
+// encodeArray writes the solutions as JSON array. The encoder needs to write
+// JSON.
+func (g *genericEncoder[Solution, Options]) encodeArray(
+	w io.Writer, solutions <-chan Solution,
+) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	separator := "\n"
+	for solution := range solutions {
+		if _, err := io.WriteString(w, separator); err != nil {
+			return err
+		}
+		if err := g.encoder.Encode(w, solution); err != nil {
+			return err
+		}
+		separator = ",\n"
+	}
+	_, err := io.WriteString(w, "]\n")
+	return err
+}
+
+// encodeNDJSON writes each solution as compact JSON on a line of its own. The
+// encoder needs to write JSON.
+func (g *genericEncoder[Solution, Options]) encodeNDJSON(
+	w io.Writer, solutions <-chan Solution,
+) error {
+	var encoded, compacted bytes.Buffer
+	for solution := range solutions {
+		encoded.Reset()
+		compacted.Reset()
+		if err := g.encoder.Encode(&encoded, solution); err != nil {
+			return err
+		}
+		if err := json.Compact(&compacted, encoded.Bytes()); err != nil {
+			return fmt.Errorf("ndjson output requires a JSON encoder: %w", err)
+		}
+		compacted.WriteByte('\n')
+		if _, err := compacted.WriteTo(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeSplit writes each solution to a file of its own in the directory and
+// a manifest listing them. The directory is a URI resolved with the storage
+// package.
+func (g *genericEncoder[Solution, Options]) encodeSplit(
+	ctx context.Context, dir string, solutions <-chan Solution,
+) (err error) {
+	extension := extensionOf(g.ContentType())
+	manifest := Manifest{Solutions: []ManifestEntry{}}
+	for solution := range solutions {
+		entry := ManifestEntry{
+			File: fmt.Sprintf(
+				"solution-%04d%s", len(manifest.Solutions)+1, extension,
+			),
+			Time:      time.Now(),
+			Objective: objectiveOf(solution),
+		}
+		err := g.encodeFile(ctx, storage.Join(dir, entry.File), solution)
+		if err != nil {
+			return err
+		}
+		manifest.Solutions = append(manifest.Solutions, entry)
+	}
+	file, err := storage.Create(ctx, storage.Join(dir, ManifestFile))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		tempErr := file.Close()
+		if err == nil {
+			err = tempErr
+		}
+	}()
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(manifest)
+}
+
+func (g *genericEncoder[Solution, Options]) encodeFile(
+	ctx context.Context, uri string, solution Solution,
+) (err error) {
+	file, err := storage.Create(ctx, uri)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		tempErr := file.Close()
+		if err == nil {
+			err = tempErr
+		}
+	}()
+	return g.encoder.Encode(file, solution)
+}
+
+// extensionOf returns the file extension of solutions of the content type.
+func extensionOf(contentType string) string {
+	switch contentType {
+	case "application/json":
+		return ".json"
+	case "application/xml":
+		return ".xml"
+	case "application/gob":
+		return ".gob"
+	default:
+		return ".txt"
+	}
+}
+
+// objectiveOf returns the result value in the statistics of the solution, if
+// it is a schema.Output.
+func objectiveOf(solution any) *statistics.Float64 {
+	var output *schema.Output
+	switch s := solution.(type) {
+	case schema.Output:
+		output = &s
+	case *schema.Output:
+		output = s
+	}
+	if output == nil || output.Statistics == nil ||
+		output.Statistics.Result == nil {
+		return nil
+	}
+	return output.Statistics.Result.Value
+}
@@ -0,0 +1,101 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+type provenOptimalKey struct{}
+
+// ProvenOptimal reports that the last solution the algorithm sent is optimal.
+// In a Portfolio, the other algorithms are cancelled. Outside of a Portfolio,
+// it does nothing.
+func ProvenOptimal(ctx context.Context) {
+	if cancel, ok := ctx.Value(provenOptimalKey{}).(context.CancelFunc); ok {
+		cancel()
+	}
+}
+
+// Portfolio returns an algorithm racing the given algorithms on the same input
+// and options. Their solutions are merged, so only solutions improving on the
+// best one sent so far are sent. compare returns a negative number, if
+// solution a is better than solution b. All algorithms are cancelled as soon
+// as one of them calls ProvenOptimal or the context is done, e.g. because its
+// deadline passed.
+//
+// The algorithms run concurrently and all receive the same input and options,
+// which share maps, slices and pointers. Algorithms must therefore not modify
+// them; an algorithm changing its input has to work on its own copy.
+//
+// An algorithm failing does not stop the others. An error is only returned,
+// if all algorithms fail, joining their errors. Errors of algorithms reacting
+// to being cancelled are ignored.
+func Portfolio[Input, Option, Solution any](
+	compare func(a, b Solution) int,
+	algorithms ...Algorithm[Input, Option, Solution],
+) Algorithm[Input, Option, Solution] {
+	return func(
+		ctx context.Context,
+		input Input,
+		option Option,
+		solutions chan<- Solution,
+	) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		ctx = context.WithValue(ctx, provenOptimalKey{}, cancel)
+
+		var mutex sync.Mutex
+		var best Solution
+		found := false
+		// forward sends the solution, if it improves on the best one. The
+		// lock is held while sending, so the solutions sent keep improving.
+		forward := func(solution Solution) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			if found && compare(solution, best) >= 0 {
+				return
+			}
+			best, found = solution, true
+			solutions <- solution
+		}
+
+		errs := make([]error, len(algorithms))
+		var wg sync.WaitGroup
+		for i, algorithm := range algorithms {
+			wg.Add(1)
+			go func(i int, algorithm Algorithm[Input, Option, Solution]) {
+				defer wg.Done()
+				member := make(chan Solution)
+				forwarded := make(chan struct{})
+				go func() {
+					defer close(forwarded)
+					for solution := range member {
+						forward(solution)
+					}
+				}()
+				errs[i] = algorithm(ctx, input, option, member)
+				close(member)
+				<-forwarded
+			}(i, algorithm)
+		}
+		wg.Wait()
+
+		var failures []error
+		for _, err := range errs {
+			if err == nil || isCancellationError(ctx, err) {
+				return nil
+			}
+			failures = append(failures, err)
+		}
+		return errors.Join(failures...)
+	}
+}
+
+// isCancellationError reports whether err is an algorithm reacting to the
+// context being done.
+func isCancellationError(ctx context.Context, err error) bool {
+	return ctx.Err() != nil && (errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, context.Cause(ctx)))
+}
@@ -0,0 +1,98 @@
+package run
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// startCPUProfile starts profiling the CPU to the file at the given path. The
+// returned function stops profiling and closes the file.
+func startCPUProfile(path string) (stop func() error, err error) {
+	stop = func() error {
+		return nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return stop, err
+	}
+	stop = func() error {
+		return f.Close()
+	}
+
+	if err := pprof.StartCPUProfile(f); err != nil {
+		return stop, err
+	}
+	stop = func() error {
+		pprof.StopCPUProfile()
+		return f.Close()
+	}
+	return stop, nil
+}
+
+// writeHeapProfile writes a heap profile to the file at the given path.
+func writeHeapProfile(path string) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		tempErr := f.Close()
+		// the first error is the most important
+		if err == nil {
+			err = tempErr
+		}
+	}()
+
+	// Clean up unused objects from the heap before profiling.
+	runtime.GC()
+
+	return pprof.WriteHeapProfile(f)
+}
+
+// startTrace starts an execution trace written to the file at the given path.
+// The returned function stops tracing and closes the file.
+func startTrace(path string) (stop func() error, err error) {
+	stop = func() error {
+		return nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return stop, err
+	}
+	stop = func() error {
+		return f.Close()
+	}
+
+	if err := trace.Start(f); err != nil {
+		return stop, err
+	}
+	stop = func() error {
+		trace.Stop()
+		return f.Close()
+	}
+	return stop, nil
+}
+
+// startSampledProfile enables sampling of the named runtime profile, e.g.
+// "block" or "mutex". The returned function disables sampling again and writes
+// the profile to the file at the given path.
+func startSampledProfile(
+	name string, path string, setRate func(int) int,
+) (stop func() error, err error) {
+	stop = func() error {
+		return nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return stop, err
+	}
+	previousRate := setRate(1)
+	return func() error {
+		defer setRate(previousRate)
+		err := pprof.Lookup(name).WriteTo(f, 0)
+		return errors.Join(err, f.Close())
+	}, nil
+}
@@ -0,0 +1,209 @@
+package run
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/nextmv-io/sdk/run/statistics"
+)
+
+// Progress is the progress of a run, as reported with Report.
+type Progress struct {
+	// Fraction is the completed fraction of the run, between 0 and 1.
+	Fraction float64 `json:"fraction"`
+	Message  string  `json:"message,omitempty"`
+	// Elapsed is the duration of the run so far in seconds.
+	Elapsed float64 `json:"elapsed"`
+	// ETA is the estimated remaining duration of the run in seconds. It is
+	// extrapolated from the elapsed duration and the fraction, so it is only
+	// known once the fraction is positive.
+	ETA *float64 `json:"eta,omitempty"`
+}
+
+type progressKey struct{}
+
+// Report reports the progress of the run. The fraction is the completed
+// fraction of the run, between 0 and 1. The CLI and worker runners log the
+// progress to stderr, at most once a second. The HTTPRunner serves it at
+// /runs/{request_id} and streams it as server-sent events from
+// /runs/{request_id}/progress. The last progress is recorded in the run
+// statistics of schema.Output solutions. Outside of a run, it does nothing.
+func Report(ctx context.Context, fraction float64, message string) {
+	if tracker, ok := ctx.Value(progressKey{}).(*progressTracker); ok {
+		tracker.report(fraction, message)
+	}
+}
+
+// progressTracker keeps the last progress of a run and passes reports on to
+// its subscribers. It is safe for concurrent use.
+type progressTracker struct {
+	start time.Time
+	// sink is called with the reports at most once per sinkInterval, if it
+	// is not nil. A report completing the run is passed on right away, the
+	// last one held back by flush.
+	sink         func(Progress)
+	sinkInterval time.Duration
+	mutex        sync.Mutex
+	last         *Progress
+	// sunk is the time the sink was called last.
+	sunk time.Time
+	// pending is the report held back from the sink, if any.
+	pending     *Progress
+	subscribers map[chan Progress]struct{}
+	closed      bool
+}
+
+func newProgressTracker(
+	sink func(Progress), sinkInterval time.Duration,
+) *progressTracker {
+	return &progressTracker{
+		start:        time.Now(),
+		sink:         sink,
+		sinkInterval: sinkInterval,
+		subscribers:  map[chan Progress]struct{}{},
+	}
+}
+
+// withProgress returns a context tracking the progress of the run and a
+// function to call once the run is done. If the context already tracks it,
+// e.g. because the HTTPRunner serves it, it is returned as is. Otherwise the
+// progress is logged at most once a second and the last report once the run
+// is done.
+func withProgress(ctx context.Context) (context.Context, func()) {
+	if _, ok := ctx.Value(progressKey{}).(*progressTracker); ok {
+		return ctx, func() {}
+	}
+	tracker := newProgressTracker(logProgress, time.Second)
+	return context.WithValue(ctx, progressKey{}, tracker), tracker.flush
+}
+
+// logProgress logs the progress to stderr.
+func logProgress(progress Progress) {
+	eta := "unknown"
+	if progress.ETA != nil {
+		eta = time.Duration(*progress.ETA * float64(time.Second)).
+			Round(time.Millisecond).String()
+	}
+	log.Printf("progress %.1f%% (eta %s) %s",
+		progress.Fraction*100, eta, progress.Message)
+}
+
+func (t *progressTracker) report(fraction float64, message string) {
+	fraction = min(max(fraction, 0), 1)
+	elapsed := time.Since(t.start).Seconds()
+	progress := Progress{
+		Fraction: fraction,
+		Message:  message,
+		Elapsed:  elapsed,
+	}
+	if fraction > 0 {
+		eta := elapsed * (1 - fraction) / fraction
+		progress.ETA = &eta
+	}
+
+	t.mutex.Lock()
+	t.last = &progress
+	for subscriber := range t.subscribers {
+		// subscribers only need the latest progress, so an unread one is
+		// replaced.
+		select {
+		case <-subscriber:
+		default:
+		}
+		subscriber <- progress
+	}
+	sink := t.sink != nil &&
+		(fraction >= 1 || time.Since(t.sunk) >= t.sinkInterval)
+	if sink {
+		t.sunk, t.pending = time.Now(), nil
+	} else if t.sink != nil {
+		t.pending = &progress
+	}
+	t.mutex.Unlock()
+
+	// the sink may be slow, e.g. when logging, so it does not hold up other
+	// reports.
+	if sink {
+		t.sink(progress)
+	}
+}
+
+// flush passes the report held back from the sink on, if any.
+func (t *progressTracker) flush() {
+	t.mutex.Lock()
+	pending := t.pending
+	t.pending = nil
+	t.mutex.Unlock()
+	if pending != nil {
+		t.sink(*pending)
+	}
+}
+
+// latest returns the last progress reported, if any.
+func (t *progressTracker) latest() (Progress, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.last == nil {
+		return Progress{}, false
+	}
+	return *t.last, true
+}
+
+// subscribe returns a channel receiving the progress reported from now on,
+// starting with the last one. The channel is closed once the run is done or
+// unsubscribe is called.
+func (t *progressTracker) subscribe() (<-chan Progress, func()) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	subscriber := make(chan Progress, 1)
+	if t.last != nil {
+		subscriber <- *t.last
+	}
+	if t.closed {
+		close(subscriber)
+		return subscriber, func() {}
+	}
+	t.subscribers[subscriber] = struct{}{}
+	return subscriber, func() {
+		t.mutex.Lock()
+		defer t.mutex.Unlock()
+		if _, ok := t.subscribers[subscriber]; ok {
+			delete(t.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+// close closes the channels of the subscribers.
+func (t *progressTracker) close() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.closed = true
+	for subscriber := range t.subscribers {
+		close(subscriber)
+	}
+	t.subscribers = map[chan Progress]struct{}{}
+}
+
+// markProgress records the last progress of the run in the run statistics of
+// the solution, if the solution is a schema.Output.
+func markProgress[Solution any](
+	ctx context.Context, solution Solution,
+) Solution {
+	tracker, ok := ctx.Value(progressKey{}).(*progressTracker)
+	if !ok {
+		return solution
+	}
+	progress, ok := tracker.latest()
+	if !ok {
+		return solution
+	}
+	return updateRunStatistics(solution, func(run *statistics.Run) {
+		run.Progress = &statistics.Progress{
+			Fraction: progress.Fraction,
+			Message:  progress.Message,
+		}
+	})
+}
@@ -1,5 +1,7 @@
 package run
 
+import "github.com/nextmv-io/sdk/run/trace"
+
 // RunnerOption configures a Runner.
 type RunnerOption[RunnerConfig, Input, Option, Solution any] func(
 	Runner[RunnerConfig, Input, Option, Solution],
@@ -38,6 +40,47 @@ func OptionDecode[
 	}
 }
 
+// solutionDecoderSetter is implemented by runners accepting a decoder of
+// initial solutions.
+type solutionDecoderSetter[Solution any] interface {
+	SetSolutionDecoder(Decoder[Solution])
+}
+
+// SolutionDecode sets the decoder of initial solutions of a runner. Runners
+// not accepting initial solutions are left as is.
+func SolutionDecode[
+	RunnerConfig, Input, Option, Solution any,
+](d Decoder[Solution]) func(
+	Runner[RunnerConfig, Input, Option, Solution],
+) {
+	return func(r Runner[RunnerConfig, Input, Option, Solution]) {
+		if setter, ok := r.(solutionDecoderSetter[Solution]); ok {
+			setter.SetSolutionDecoder(d)
+		}
+	}
+}
+
+// solutionValidatorSetter is implemented by runners validating their
+// solutions.
+type solutionValidatorSetter[Solution any] interface {
+	SetSolutionValidator(Validator[Solution])
+}
+
+// SolutionValidate sets the validator of the solutions of a runner. It is
+// used, if the runner configuration enables output validation, see
+// OutputValidator. Runners not validating solutions are left as is.
+func SolutionValidate[
+	RunnerConfig, Input, Option, Solution any,
+](v Validator[Solution]) func(
+	Runner[RunnerConfig, Input, Option, Solution],
+) {
+	return func(r Runner[RunnerConfig, Input, Option, Solution]) {
+		if setter, ok := r.(solutionValidatorSetter[Solution]); ok {
+			setter.SetSolutionValidator(v)
+		}
+	}
+}
+
 // Encode sets the encoder of a runner.
 func Encode[
 	RunnerConfig, Input, Option, Solution any,
@@ -59,3 +102,22 @@ func IOProduce[
 		r.SetIOProducer(i)
 	}
 }
+
+// tracerSetter is implemented by runners instrumenting their stages.
+type tracerSetter interface {
+	SetTracer(trace.Tracer)
+}
+
+// Trace sets the tracer of a runner. Runners not instrumenting their stages
+// are left as is.
+func Trace[
+	RunnerConfig, Input, Option, Solution any,
+](t trace.Tracer) func(
+	Runner[RunnerConfig, Input, Option, Solution],
+) {
+	return func(r Runner[RunnerConfig, Input, Option, Solution]) {
+		if setter, ok := r.(tracerSetter); ok {
+			setter.SetTracer(t)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Package runtest runs algorithms in process, the way a CLI runner would, so
+// they can be tested without building and executing a binary. Runners are
+// configured with explicit arguments and environment variables, leaving the
+// flags and the environment of the test binary untouched.
+package runtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/nextmv-io/sdk/run"
+	"github.com/nextmv-io/sdk/run/decode"
+	"github.com/nextmv-io/sdk/run/encode"
+	"github.com/nextmv-io/sdk/run/validate"
+)
+
+// Option configures a run.
+type Option func(*settings)
+
+type settings struct {
+	args            []string
+	env             []string
+	initialSolution []byte
+	// runnerOptions holds the run.RunnerOption values of the run, their
+	// type parameters are only known to Run.
+	runnerOptions []any
+}
+
+// Args sets the command line arguments of the run, e.g. "-duration=1s" or
+// "-runner.output.solutions=all".
+func Args(args ...string) Option {
+	return func(s *settings) { s.args = append(s.args, args...) }
+}
+
+// Env sets the environment variables of the run, given as "NAME=value".
+func Env(env ...string) Option {
+	return func(s *settings) { s.env = append(s.env, env...) }
+}
+
+// InitialSolution sets the JSON encoded solution to warm-start the algorithm
+// with.
+func InitialSolution(solution []byte) Option {
+	return func(s *settings) { s.initialSolution = solution }
+}
+
+// RunnerOptions sets options of the runner, e.g. run.Trace or
+// run.SolutionValidate. The type parameters need to match the ones of the
+// algorithm passed to Run. Options replacing the encoder or the IOProducer
+// break how Run collects the solutions.
+func RunnerOptions[Input, Options, Solution any](
+	options ...run.RunnerOption[
+		run.CLIRunnerConfig, Input, Options, Solution,
+	],
+) Option {
+	return func(s *settings) {
+		for _, option := range options {
+			s.runnerOptions = append(s.runnerOptions, option)
+		}
+	}
+}
+
+// Run runs the algorithm on the JSON encoded input and returns the solutions
+// it wrote. The input is validated and decoded, and the solutions are encoded
+// as JSON, as by run.NewCLIRunner. As there, only the last solution is
+// returned, unless the run is configured with
+// Args("-runner.output.solutions=all"). Input and output paths are not read
+// or written.
+func Run[Input, Options, Solution any](
+	ctx context.Context,
+	algorithm run.Algorithm[Input, Options, Solution],
+	input []byte,
+	options ...Option,
+) ([]Solution, error) {
+	s := settings{}
+	for _, option := range options {
+		option(&s)
+	}
+
+	var output bytes.Buffer
+	producer := func(
+		_ context.Context, _ run.CLIRunnerConfig,
+	) (run.IOData, error) {
+		var ioOptions []run.IODataOption
+		if s.initialSolution != nil {
+			ioOptions = append(ioOptions, run.WithInitialSolution(
+				bytes.NewReader(s.initialSolution),
+			))
+		}
+		return run.NewIOData(bytes.NewReader(input), nil, &output, ioOptions...)
+	}
+	runner, err := run.GenericRunnerFromArgs(
+		s.args,
+		s.env,
+		producer,
+		run.GenericDecoder[Input](decode.JSON()),
+		validate.JSON[Input](nil),
+		run.NoopOptionsDecoder[Options],
+		algorithm,
+		run.GenericEncoder[Solution, Options](encode.JSON()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, option := range s.runnerOptions {
+		runnerOption, ok := option.(run.RunnerOption[
+			run.CLIRunnerConfig, Input, Options, Solution,
+		])
+		if !ok {
+			return nil, fmt.Errorf(
+				"runner option %T does not match the algorithm", option,
+			)
+		}
+		runnerOption(runner)
+	}
+	if err := runner.Run(ctx); err != nil {
+		return nil, err
+	}
+
+	var solutions []Solution
+	decoder := json.NewDecoder(&output)
+	for {
+		var solution Solution
+		err := decoder.Decode(&solution)
+		if errors.Is(err, io.EOF) {
+			return solutions, nil
+		}
+		if err != nil {
+			return solutions, err
+		}
+		solutions = append(solutions, solution)
+	}
+}
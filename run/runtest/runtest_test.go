@@ -0,0 +1,124 @@
+package runtest_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nextmv-io/sdk/run"
+	"github.com/nextmv-io/sdk/run/runtest"
+)
+
+type input struct {
+	Message string `json:"message"`
+}
+
+type option struct {
+	Repeat int           `json:"repeat" default:"1" usage:"Number of solutions."`
+	Delay  time.Duration `json:"delay" usage:"Delay between solutions."`
+	Suffix string        `json:"suffix" env:"message_suffix" usage:"Suffix."`
+}
+
+type output struct {
+	Message string `json:"message"`
+	Index   int    `json:"index"`
+}
+
+func algorithm(
+	_ context.Context, input input, opts option, solutions chan<- output,
+) error {
+	for i := 0; i < opts.Repeat; i++ {
+		time.Sleep(opts.Delay)
+		solutions <- output{Message: input.Message + opts.Suffix, Index: i}
+	}
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []runtest.Option
+		want    []output
+	}{
+		{
+			name: "defaults",
+			want: []output{{Message: "hi", Index: 0}},
+		},
+		{
+			name:    "last solution",
+			options: []runtest.Option{runtest.Args("-repeat=3")},
+			want:    []output{{Message: "hi", Index: 2}},
+		},
+		{
+			name: "all solutions",
+			options: []runtest.Option{
+				runtest.Args("-repeat=2", "-runner.output.solutions=all"),
+			},
+			want: []output{{Message: "hi", Index: 0}, {Message: "hi", Index: 1}},
+		},
+		{
+			name: "environment",
+			options: []runtest.Option{
+				runtest.Env("REPEAT=2", "RUNNER_OUTPUT_SOLUTIONS=all"),
+			},
+			want: []output{{Message: "hi", Index: 0}, {Message: "hi", Index: 1}},
+		},
+		{
+			name: "arguments override environment",
+			options: []runtest.Option{
+				runtest.Env("REPEAT=2"),
+				runtest.Args("-repeat=1"),
+			},
+			want: []output{{Message: "hi", Index: 0}},
+		},
+		{
+			name:    "environment variable set by tag",
+			options: []runtest.Option{runtest.Env("message_suffix=!")},
+			want:    []output{{Message: "hi!", Index: 0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runtest.Run(
+				context.Background(),
+				algorithm,
+				[]byte(`{"message": "hi"}`),
+				tt.options...,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	if _, err := runtest.Run(
+		context.Background(), algorithm, []byte(`{"message": "hi"}`),
+		runtest.Args("-unknown"),
+	); err == nil {
+		t.Error("expected error for unknown flag")
+	}
+	if _, err := runtest.Run(
+		context.Background(), algorithm, []byte(`{"message": 1}`),
+	); err == nil {
+		t.Error("expected error for invalid input")
+	}
+	if _, err := runtest.Run(
+		context.Background(), algorithm, []byte(`{"message": "hi"}`),
+		runtest.RunnerOptions(
+			run.InputValidate[run.CLIRunnerConfig, input, option, output](
+				func(context.Context, any) error {
+					return errors.New("rejected")
+				},
+			),
+		),
+	); err == nil {
+		t.Error("expected error of the input validator option")
+	}
+}
@@ -0,0 +1,61 @@
+package schema
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// BuildTime is the time the application was built. It is empty, unless it is
+// set when linking, e.g.:
+//
+//	go build -ldflags "-X github.com/nextmv-io/sdk/run/schema.BuildTime=$(date -u +%FT%TZ)"
+var BuildTime string
+
+// BuildInfo is the build information of the application.
+type BuildInfo struct {
+	// Module is the path of the main module of the application.
+	Module string `json:"module,omitempty"`
+	// Version is the version of the main module of the application. It is
+	// "(devel)", unless the application was installed with a version.
+	Version   string `json:"version,omitempty"`
+	GoVersion string `json:"go_version"`
+	// Revision is the VCS revision the application was built from.
+	Revision string `json:"revision,omitempty"`
+	// RevisionTime is the time of the VCS revision.
+	RevisionTime string `json:"revision_time,omitempty"`
+	// Modified is true, if the working tree had local changes.
+	Modified bool `json:"modified,omitempty"`
+	// BuildTime is the time the application was built, see BuildTime.
+	BuildTime string `json:"build_time,omitempty"`
+	// Dependencies are the versions of the known dependencies, see
+	// AddKnownDependency.
+	Dependencies Version `json:"dependencies"`
+}
+
+// ReadBuildInfo returns the build information of the application, as embedded
+// in the binary.
+func ReadBuildInfo() BuildInfo {
+	info := BuildInfo{
+		GoVersion:    runtime.Version(),
+		BuildTime:    BuildTime,
+		Dependencies: Version{},
+	}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.Module = bi.Main.Path
+	info.Version = bi.Main.Version
+	info.Dependencies = knownDependencyVersions(bi)
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.time":
+			info.RevisionTime = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}
@@ -4,6 +4,7 @@ package schema
 import (
 	"runtime/debug"
 	"strings"
+	"sync"
 
 	"github.com/nextmv-io/sdk/run/statistics"
 )
@@ -35,12 +36,16 @@ func NewOutput[Solution any](options any, solutions ...Solution) Output {
 	}
 }
 
-// knownDependencies is a list of known dependencies that we want to put in the
-// version of the output.
-var knownDependencies = []struct {
+// knownDependency is a dependency whose version is put in the version of the
+// output.
+type knownDependency struct {
 	name string
 	path string
-}{
+}
+
+// knownDependencies is a list of known dependencies that we want to put in the
+// version of the output.
+var knownDependencies = []knownDependency{
 	{name: "sdk", path: "github.com/nextmv-io/sdk"},
 	{name: "nextroute", path: "github.com/nextmv-io/nextroute"},
 	{name: "go-mip", path: "github.com/nextmv-io/go-mip"},
@@ -48,6 +53,26 @@ var knownDependencies = []struct {
 	{name: "go-xpress", path: "github.com/nextmv-io/go-xpress"},
 }
 
+var knownDependenciesMutex sync.RWMutex
+
+// AddKnownDependency adds a dependency whose version is reported under the
+// name in the version of outputs and in the BuildInfo. Dependencies are
+// matched by their module path or a path below it. Adding a name again
+// replaces the path of the name.
+func AddKnownDependency(name, path string) {
+	knownDependenciesMutex.Lock()
+	defer knownDependenciesMutex.Unlock()
+	for i, knownDep := range knownDependencies {
+		if knownDep.name == name {
+			knownDependencies[i].path = path
+			return
+		}
+	}
+	knownDependencies = append(
+		knownDependencies, knownDependency{name: name, path: path},
+	)
+}
+
 func collectKnownDependencies() Version {
 	// We use the debug.ReadBuildInfo to get the version of the dependencies.
 	bi, ok := debug.ReadBuildInfo()
@@ -56,12 +81,20 @@ func collectKnownDependencies() Version {
 		// provide the version of the dependencies.
 		return map[string]string{}
 	}
+	return knownDependencyVersions(bi)
+}
 
+// knownDependencyVersions returns the versions of the known dependencies of
+// the build.
+func knownDependencyVersions(bi *debug.BuildInfo) Version {
+	knownDependenciesMutex.RLock()
+	defer knownDependenciesMutex.RUnlock()
 	// Search all dependencies for known ones and collect their versions.
 	deps := map[string]string{}
 	for _, dep := range bi.Deps {
 		for _, knownDep := range knownDependencies {
-			if strings.HasPrefix(dep.Path, knownDep.path) {
+			if dep.Path == knownDep.path ||
+				strings.HasPrefix(dep.Path, knownDep.path+"/") {
 				deps[knownDep.name] = dep.Version
 			}
 		}
@@ -0,0 +1,24 @@
+package schema
+
+import (
+	"reflect"
+	"runtime/debug"
+	"testing"
+)
+
+func TestKnownDependencyVersions(t *testing.T) {
+	AddKnownDependency("foo", "example.com/foo")
+	AddKnownDependency("foo", "example.com/bar")
+	bi := &debug.BuildInfo{Deps: []*debug.Module{
+		{Path: "github.com/nextmv-io/sdk", Version: "v1.0.0"},
+		{Path: "github.com/nextmv-io/sdk-foo", Version: "v2.0.0"},
+		{Path: "github.com/nextmv-io/nextroute/v2", Version: "v2.1.0"},
+		{Path: "example.com/foo", Version: "v3.0.0"},
+		{Path: "example.com/bar", Version: "v4.0.0"},
+	}}
+	got := knownDependencyVersions(bi)
+	want := Version{"sdk": "v1.0.0", "nextroute": "v2.1.0", "foo": "v4.0.0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,96 @@
+package run
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/nextmv-io/sdk/run/statistics"
+)
+
+// Seeder is the interface a runner configuration can implement to set the
+// seed of the random number generators of a run. A seed of 0 means a seed is
+// generated for each run.
+type Seeder interface {
+	Seed() int64
+}
+
+type seedKey struct{}
+
+// randFactory creates the random number generators of a run.
+type randFactory struct {
+	seed  int64
+	mutex sync.Mutex
+	count int64
+}
+
+// Seed returns the seed of the run. It is recorded in the run statistics of
+// schema.Output solutions, so a run can be reproduced by running the same
+// input with the recorded seed. Every run has a seed, so schema.Output
+// solutions always carry statistics, even if the algorithm never uses
+// randomness.
+func Seed(ctx context.Context) (int64, bool) {
+	factory, ok := ctx.Value(seedKey{}).(*randFactory)
+	if !ok {
+		return 0, false
+	}
+	return factory.seed, true
+}
+
+// NewRand returns a new random number generator of the run. The n-th
+// generator created in a run is seeded with the seed of the run and n, so runs
+// with the same seed see the same random numbers, as long as generators are
+// created in the same order. Outside of a run, the generator is seeded with
+// the current time.
+func NewRand(ctx context.Context) *rand.Rand {
+	factory, ok := ctx.Value(seedKey{}).(*randFactory)
+	if !ok {
+		return rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	factory.mutex.Lock()
+	n := factory.count
+	factory.count++
+	factory.mutex.Unlock()
+	return rand.New(rand.NewSource(deriveSeed(factory.seed, n)))
+}
+
+// deriveSeed returns the n-th seed derived from the seed, the seed itself for
+// n = 0. It mixes the bits with the SplitMix64 finalizer, so the seeds of
+// consecutive generators are not correlated.
+func deriveSeed(seed, n int64) int64 {
+	if n == 0 {
+		return seed
+	}
+	z := uint64(seed) + uint64(n)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return int64(z ^ (z >> 31))
+}
+
+// withSeed returns a context carrying the seed configured in the runner
+// configuration or, if none is configured, a generated one.
+func withSeed(ctx context.Context, runnerConfig any) context.Context {
+	var seed int64
+	if seeder, ok := runnerConfig.(Seeder); ok {
+		seed = seeder.Seed()
+	}
+	for seed == 0 {
+		// generated seeds are exactly representable as float64, so tools
+		// parsing JSON numbers as such reproduce them, too.
+		seed = rand.Int63n(1 << 53)
+	}
+	return context.WithValue(ctx, seedKey{}, &randFactory{seed: seed})
+}
+
+// markSeed records the seed of the run in the run statistics of the solution,
+// if the solution is a schema.Output.
+func markSeed[Solution any](ctx context.Context, solution Solution) Solution {
+	seed, ok := Seed(ctx)
+	if !ok {
+		return solution
+	}
+	return updateRunStatistics(solution, func(run *statistics.Run) {
+		run.Seed = &seed
+	})
+}
@@ -21,7 +21,20 @@ type Statistics struct {
 type Run struct {
 	Duration   *float64 `json:"duration,omitempty"`
 	Iterations *int     `json:"iterations,omitempty"`
-	Custom     any      `json:"custom,omitempty"`
+	// Termination is the reason the runner stopped the run early, if it did.
+	Termination string `json:"termination,omitempty"`
+	// Seed is the seed of the random number generators of the run.
+	Seed *int64 `json:"seed,omitempty"`
+	// Progress is the progress the algorithm last reported.
+	Progress *Progress `json:"progress,omitempty"`
+	Custom   any       `json:"custom,omitempty"`
+}
+
+// Progress is the structure of the progress of a run.
+type Progress struct {
+	// Fraction is the completed fraction of the run, between 0 and 1.
+	Fraction float64 `json:"fraction"`
+	Message  string  `json:"message,omitempty"`
 }
 
 // Result is the structure of the result section of the statistics.
@@ -0,0 +1,181 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GCSConfig configures the storage of gs:// URIs. Empty fields are taken from
+// the environment when a request is made.
+type GCSConfig struct {
+	// Endpoint is the URL of the Cloud Storage JSON API, defaults to
+	// http://$STORAGE_EMULATOR_HOST, if set, or https://storage.googleapis.com.
+	Endpoint string
+	// Token returns the OAuth 2.0 access token requests are authorized with.
+	// It defaults to GOOGLE_OAUTH_ACCESS_TOKEN or the token of the service
+	// account of the instance, as served by the metadata server on Google
+	// Cloud. By default, requests to the emulator are not authorized.
+	Token func(ctx context.Context) (string, error)
+	// Client sends the requests, defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// GCS returns the storage of gs://bucket/object URIs. Objects are read and
+// written with the Cloud Storage JSON API, streaming the data.
+func GCS(config GCSConfig) Storage {
+	return gcsStorage{config: config, metadataToken: &metadataToken{}}
+}
+
+type gcsStorage struct {
+	config        GCSConfig
+	metadataToken *metadataToken
+}
+
+func (s gcsStorage) Open(
+	ctx context.Context, uri string,
+) (io.ReadCloser, error) {
+	bucket, object, err := bucketAndKey(uri)
+	if err != nil {
+		return nil, err
+	}
+	req, err := s.request(ctx, http.MethodGet, fmt.Sprintf(
+		"/storage/v1/b/%s/o/%s?alt=media",
+		url.PathEscape(bucket), url.PathEscape(object),
+	), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s gcsStorage) Create(
+	ctx context.Context, uri string,
+) (io.WriteCloser, error) {
+	bucket, object, err := bucketAndKey(uri)
+	if err != nil {
+		return nil, err
+	}
+	reader, writer := io.Pipe()
+	req, err := s.request(ctx, http.MethodPost, fmt.Sprintf(
+		"/upload/storage/v1/b/%s/o?uploadType=media&name=%s",
+		url.PathEscape(bucket), url.QueryEscape(object),
+	), reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	return stream(s.client(), req, reader, writer), nil
+}
+
+func (s gcsStorage) client() *http.Client {
+	if s.config.Client != nil {
+		return s.config.Client
+	}
+	return http.DefaultClient
+}
+
+// request returns the authorized request of the path of the JSON API.
+func (s gcsStorage) request(
+	ctx context.Context, method, path string, body io.Reader,
+) (*http.Request, error) {
+	endpoint, token := s.config.Endpoint, s.config.Token
+	emulator := os.Getenv("STORAGE_EMULATOR_HOST")
+	switch {
+	case endpoint == "" && emulator != "":
+		endpoint = emulator
+		if !strings.Contains(emulator, "://") {
+			endpoint = "http://" + emulator
+		}
+	case token == nil:
+		token = s.defaultToken
+	}
+	if endpoint == "" {
+		endpoint = "https://storage.googleapis.com"
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, method, strings.TrimSuffix(endpoint, "/")+path, body,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if token != nil {
+		accessToken, err := token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("gs: getting access token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	return req, nil
+}
+
+func (s gcsStorage) defaultToken(ctx context.Context) (string, error) {
+	if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		return token, nil
+	}
+	return s.metadataToken.get(ctx, s.client())
+}
+
+// metadataToken caches the access token of the service account of the
+// instance until shortly before it expires.
+type metadataToken struct {
+	mutex   sync.Mutex
+	token   string
+	expires time.Time
+}
+
+const metadataTokenURL = "http://metadata.google.internal/computeMetadata" +
+	"/v1/instance/service-accounts/default/token"
+
+func (t *metadataToken) get(
+	ctx context.Context, client *http.Client,
+) (string, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.token != "" && time.Now().Before(t.expires) {
+		return t.token, nil
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, metadataTokenURL, nil,
+	)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf(
+			"%w, set GOOGLE_OAUTH_ACCESS_TOKEN outside of Google Cloud", err,
+		)
+	}
+	if err := checkStatus(resp); err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	t.token = token.AccessToken
+	t.expires = time.Now().Add(
+		time.Duration(token.ExpiresIn)*time.Second - time.Minute,
+	)
+	return t.token, nil
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+)
+
+// HTTP returns the storage of http:// and https:// URIs. Objects are read with
+// GET and written with PUT requests, streaming the body.
+func HTTP(client *http.Client) Storage {
+	return httpStorage{client: client}
+}
+
+type httpStorage struct {
+	client *http.Client
+}
+
+func (s httpStorage) Open(
+	ctx context.Context, uri string,
+) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s httpStorage) Create(
+	ctx context.Context, uri string,
+) (io.WriteCloser, error) {
+	reader, writer := io.Pipe()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uri, reader)
+	if err != nil {
+		return nil, err
+	}
+	return stream(s.client, req, reader, writer), nil
+}
+
+// stream sends the request in the background, its body is written to the
+// returned writer.
+func stream(
+	client *http.Client,
+	req *http.Request,
+	reader *io.PipeReader,
+	writer *io.PipeWriter,
+) io.WriteCloser {
+	w := &httpWriter{PipeWriter: writer, done: make(chan error, 1)}
+	go func() {
+		resp, err := client.Do(req)
+		if err == nil {
+			if err = checkStatus(resp); err == nil {
+				err = resp.Body.Close()
+			}
+		}
+		// unblock writes, if the request failed before reading the body
+		reader.CloseWithError(err)
+		w.done <- err
+	}()
+	return w
+}
+
+// httpWriter streams the body of a PUT request.
+type httpWriter struct {
+	*io.PipeWriter
+	done chan error
+}
+
+// Close ends the body and waits for the response.
+func (w *httpWriter) Close() error {
+	if err := w.PipeWriter.Close(); err != nil {
+		return err
+	}
+	return <-w.done
+}
+
+// CloseWithError fails the request, so the object is not written, and waits
+// for the request to end.
+func (w *httpWriter) CloseWithError(err error) error {
+	_ = w.PipeWriter.CloseWithError(err)
+	<-w.done
+	return nil
+}
+
+// checkStatus returns an error and closes the body, if the response does not
+// have a 2xx status code. The error of 404 Not Found wraps fs.ErrNotExist.
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err := fmt.Errorf(
+		"%s %s: %s: %s",
+		resp.Request.Method, resp.Request.URL, resp.Status, body,
+	)
+	if resp.StatusCode == http.StatusNotFound {
+		err = fmt.Errorf("%w: %w", fs.ErrNotExist, err)
+	}
+	return err
+}
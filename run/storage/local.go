@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Local returns the storage of local files. It accepts paths and file://
+// URIs. Missing parent directories of created files are created.
+func Local() Storage {
+	return dir{}
+}
+
+// Dir returns a storage keeping objects as files below the root directory.
+// The scheme of the URIs is ignored, so s3://bucket/key is the file
+// root/bucket/key. It is meant as fake of remote storages in tests, e.g.
+// Register("s3", Dir("testdata")).
+func Dir(root string) Storage {
+	return dir{root: root}
+}
+
+type dir struct {
+	root string
+}
+
+func (d dir) path(uri string) string {
+	path := filepath.FromSlash(trimScheme(uri))
+	if d.root == "" {
+		return path
+	}
+	return filepath.Join(d.root, path)
+}
+
+func (d dir) Open(_ context.Context, uri string) (io.ReadCloser, error) {
+	return os.Open(d.path(uri))
+}
+
+func (d dir) Create(_ context.Context, uri string) (io.WriteCloser, error) {
+	path := d.path(uri)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	return os.Create(path)
+}
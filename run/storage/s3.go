@@ -0,0 +1,297 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Config configures the storage of s3:// URIs. Empty fields are taken from
+// the standard AWS environment variables when a request is made. Credentials
+// are only taken from the fields and the environment. Instance profiles, ECS
+// task roles, web identities and shared profiles are not supported, so on EC2,
+// ECS or EKS the credentials of the role need to be exported, e.g. with aws
+// configure export-credentials --format env, or a storage built with the AWS
+// SDK needs to be registered, see Register.
+type S3Config struct {
+	// Region of the buckets, defaults to AWS_REGION, AWS_DEFAULT_REGION or
+	// us-east-1.
+	Region string
+	// Endpoint is the URL of an S3 compatible service, defaults to
+	// AWS_ENDPOINT_URL_S3 or AWS_ENDPOINT_URL. Buckets are addressed in the
+	// path of a custom endpoint and as virtual host otherwise.
+	Endpoint string
+	// AccessKeyID defaults to AWS_ACCESS_KEY_ID.
+	AccessKeyID string
+	// SecretAccessKey defaults to AWS_SECRET_ACCESS_KEY.
+	SecretAccessKey string
+	// SessionToken of temporary credentials, defaults to AWS_SESSION_TOKEN.
+	SessionToken string
+	// Client sends the requests, defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// S3 returns the storage of s3://bucket/key URIs. Requests are signed with
+// AWS Signature Version 4. Objects are written with a single PUT request once
+// the writer is closed, so the whole object is buffered in memory and it is
+// limited to the 5 GB a single PUT accepts.
+func S3(config S3Config) Storage {
+	return s3Storage{config: config}
+}
+
+type s3Storage struct {
+	config S3Config
+}
+
+func (s s3Storage) Open(
+	ctx context.Context, uri string,
+) (io.ReadCloser, error) {
+	req, err := s.request(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s s3Storage) Create(
+	ctx context.Context, uri string,
+) (io.WriteCloser, error) {
+	// fail early on malformed URIs, instead of once the object is written.
+	if _, _, err := bucketAndKey(uri); err != nil {
+		return nil, err
+	}
+	return &s3Writer{ctx: ctx, storage: s, uri: uri}, nil
+}
+
+// s3Writer buffers an object and puts it when closed.
+type s3Writer struct {
+	bytes.Buffer
+	ctx     context.Context
+	storage s3Storage
+	uri     string
+}
+
+func (w *s3Writer) Close() error {
+	req, err := w.storage.request(w.ctx, http.MethodPut, w.uri, w.Bytes())
+	if err != nil {
+		return err
+	}
+	resp, err := w.storage.client().Do(req)
+	if err != nil {
+		return err
+	}
+	if err := checkStatus(resp); err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// CloseWithError discards the buffered object without putting it.
+func (w *s3Writer) CloseWithError(error) error {
+	w.Reset()
+	return nil
+}
+
+func (s s3Storage) client() *http.Client {
+	if s.config.Client != nil {
+		return s.config.Client
+	}
+	return http.DefaultClient
+}
+
+// request returns the signed request of the object at the URI.
+func (s s3Storage) request(
+	ctx context.Context, method, uri string, body []byte,
+) (*http.Request, error) {
+	bucket, key, err := bucketAndKey(uri)
+	if err != nil {
+		return nil, err
+	}
+	region := firstOf(
+		s.config.Region, os.Getenv("AWS_REGION"),
+		os.Getenv("AWS_DEFAULT_REGION"), "us-east-1",
+	)
+	endpoint := firstOf(
+		s.config.Endpoint, os.Getenv("AWS_ENDPOINT_URL_S3"),
+		os.Getenv("AWS_ENDPOINT_URL"),
+	)
+	path := "/" + uriEncode(key, false)
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucket, region)
+	} else {
+		path = "/" + uriEncode(bucket, false) + path
+	}
+	target, err := url.Parse(strings.TrimSuffix(endpoint, "/") + path)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, method, target.String(), bytes.NewReader(body),
+	)
+	if err != nil {
+		return nil, err
+	}
+	credentials := s3Credentials{
+		accessKeyID: firstOf(
+			s.config.AccessKeyID, os.Getenv("AWS_ACCESS_KEY_ID"),
+		),
+		secretAccessKey: firstOf(
+			s.config.SecretAccessKey, os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		),
+		sessionToken: firstOf(
+			s.config.SessionToken, os.Getenv("AWS_SESSION_TOKEN"),
+		),
+	}
+	if credentials.accessKeyID == "" || credentials.secretAccessKey == "" {
+		return nil, errors.New("s3: no credentials, set AWS_ACCESS_KEY_ID " +
+			"and AWS_SECRET_ACCESS_KEY")
+	}
+	if credentials.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", credentials.sessionToken)
+	}
+	hash := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(hash[:]))
+	signV4(req, credentials, region, "s3", time.Now())
+	return req, nil
+}
+
+type s3Credentials struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+}
+
+// signV4 signs the request with AWS Signature Version 4, see
+// https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_sigv-create-signed-request.html.
+// All headers of the request are signed, the X-Amz-Content-Sha256 header
+// needs to be set.
+func signV4(
+	req *http.Request,
+	credentials s3Credentials,
+	region, service string,
+	now time.Time,
+) {
+	now = now.UTC()
+	date := now.Format("20060102")
+	timestamp := now.Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", timestamp)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(
+			strings.Join(values, ","),
+		)
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", name, headers[name])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	query := req.URL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parameters := []string{}
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			parameters = append(parameters,
+				uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		strings.Join(parameters, "&"),
+		canonicalHeaders.String(),
+		signedHeaders,
+		req.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + timestamp + "\n" + scope + "\n" +
+		hex.EncodeToString(requestHash[:])
+
+	key := []byte("AWS4" + credentials.secretAccessKey)
+	for _, part := range []string{date, region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		credentials.accessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// uriEncode percent-encodes all bytes except the unreserved characters of RFC
+// 3986, as AWS expects. Slashes are only encoded, if encodeSlash is true.
+func uriEncode(s string, encodeSlash bool) string {
+	var encoded strings.Builder
+	for _, b := range []byte(s) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9',
+			b == '-', b == '.', b == '_', b == '~',
+			b == '/' && !encodeSlash:
+			encoded.WriteByte(b)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+	return encoded.String()
+}
+
+// bucketAndKey returns the bucket and the key of the object at a
+// scheme://bucket/key URI.
+func bucketAndKey(uri string) (bucket, key string, err error) {
+	bucket, key, _ = strings.Cut(trimScheme(uri), "/")
+	if bucket == "" || key == "" {
+		return "", "", fmt.Errorf(
+			"%s is not a URI of an object, want scheme://bucket/key", uri,
+		)
+	}
+	return bucket, key, nil
+}
+
+// firstOf returns the first non-empty value.
+func firstOf(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
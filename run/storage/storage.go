@@ -0,0 +1,117 @@
+// Package storage provides access to inputs and outputs by URI. The scheme of
+// the URI selects the Storage: local paths and file:// URIs are files, http://
+// and https:// URIs are read with GET and written with PUT requests, s3:// URIs
+// are objects in Amazon S3, see S3, and gs:// URIs are objects in Google Cloud
+// Storage, see GCS. Both are configured from the environment by default. The
+// storage of a scheme can be replaced with Register, e.g. to configure it
+// explicitly or to serve it from a local directory in tests, see Dir.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Storage reads and writes objects. The methods are given the full URI of the
+// object.
+type Storage interface {
+	// Open opens the object for reading.
+	Open(ctx context.Context, uri string) (io.ReadCloser, error)
+	// Create creates or truncates the object for writing. The object is
+	// complete once the writer is closed without error. If the writer
+	// implements Aborter, the object is discarded when it is aborted.
+	Create(ctx context.Context, uri string) (io.WriteCloser, error)
+}
+
+// Aborter is implemented by the writers of remote objects, which are only
+// written once the writer is closed. CloseWithError ends the writer without
+// writing the object, e.g. if producing its data failed. Either Close or
+// CloseWithError must be called to release the writer.
+type Aborter interface {
+	CloseWithError(err error) error
+}
+
+var (
+	mutex    sync.RWMutex
+	registry = map[string]Storage{
+		"file":  Local(),
+		"http":  HTTP(http.DefaultClient),
+		"https": HTTP(http.DefaultClient),
+		"s3":    S3(S3Config{}),
+		"gs":    GCS(GCSConfig{}),
+	}
+)
+
+// Register sets the storage of the URIs with the given scheme, replacing the
+// one registered before, if any.
+func Register(scheme string, storage Storage) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	registry[scheme] = storage
+}
+
+// Lookup returns the storage of the URI. URIs without scheme are local paths.
+func Lookup(uri string) (Storage, error) {
+	scheme, _, found := strings.Cut(uri, "://")
+	if !found {
+		return Local(), nil
+	}
+	mutex.RLock()
+	defer mutex.RUnlock()
+	storage, ok := registry[scheme]
+	if !ok {
+		return nil, fmt.Errorf(
+			"no storage registered for scheme %q of %s", scheme, uri,
+		)
+	}
+	return storage, nil
+}
+
+// LocalPath returns the path of the file of the URI, if the URI is stored in
+// a file by Local or Dir, e.g. a local path or a file:// URI.
+func LocalPath(uri string) (string, bool) {
+	storage, err := Lookup(uri)
+	if err != nil {
+		return "", false
+	}
+	d, ok := storage.(dir)
+	if !ok {
+		return "", false
+	}
+	return d.path(uri), true
+}
+
+// Open opens the object at the URI for reading.
+func Open(ctx context.Context, uri string) (io.ReadCloser, error) {
+	storage, err := Lookup(uri)
+	if err != nil {
+		return nil, err
+	}
+	return storage.Open(ctx, uri)
+}
+
+// Create creates the object at the URI for writing.
+func Create(ctx context.Context, uri string) (io.WriteCloser, error) {
+	storage, err := Lookup(uri)
+	if err != nil {
+		return nil, err
+	}
+	return storage.Create(ctx, uri)
+}
+
+// Join returns the URI of the object with the given name below the URI.
+func Join(uri, name string) string {
+	return strings.TrimSuffix(uri, "/") + "/" + name
+}
+
+// trimScheme returns the URI without its scheme.
+func trimScheme(uri string) string {
+	if _, rest, found := strings.Cut(uri, "://"); found {
+		return rest
+	}
+	return uri
+}
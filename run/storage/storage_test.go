@@ -0,0 +1,273 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nextmv-io/sdk/run/storage"
+)
+
+func write(t *testing.T, uri, data string) {
+	t.Helper()
+	w, err := storage.Create(context.Background(), uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(w, data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// abort writes data to the object at the URI and aborts the writer.
+func abort(t *testing.T, uri, data string) {
+	t.Helper()
+	w, err := storage.Create(context.Background(), uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(w, data); err != nil {
+		t.Fatal(err)
+	}
+	aborter, ok := w.(storage.Aborter)
+	if !ok {
+		t.Fatalf("writer %T of %s is not an Aborter", w, uri)
+	}
+	if err := aborter.CloseWithError(errors.New("aborted")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func read(t *testing.T, uri string) string {
+	t.Helper()
+	r, err := storage.Open(context.Background(), uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestDir(t *testing.T) {
+	root := t.TempDir()
+	storage.Register("fake", storage.Dir(root))
+
+	write(t, "fake://bucket/dir/object.json", "{}")
+	if got := read(t, "fake://bucket/dir/object.json"); got != "{}" {
+		t.Errorf("got %q, want %q", got, "{}")
+	}
+	data, err := os.ReadFile(filepath.Join(root, "bucket", "dir", "object.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "{}" {
+		t.Errorf("got file %q, want %q", data, "{}")
+	}
+}
+
+func TestLocal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "object.json")
+	write(t, path, "local")
+	if got := read(t, "file://"+path); got != "local" {
+		t.Errorf("got %q, want %q", got, "local")
+	}
+}
+
+func TestHTTP(t *testing.T) {
+	var mutex sync.Mutex
+	objects := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			switch req.Method {
+			case http.MethodPut:
+				data, err := io.ReadAll(req.Body)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				objects[req.URL.Path] = data
+			case http.MethodGet:
+				data, ok := objects[req.URL.Path]
+				if !ok {
+					http.NotFound(w, req)
+					return
+				}
+				_, _ = w.Write(data)
+			}
+		},
+	))
+	defer server.Close()
+
+	write(t, server.URL+"/object.json", "remote")
+	if got := read(t, server.URL+"/object.json"); got != "remote" {
+		t.Errorf("got %q, want %q", got, "remote")
+	}
+	abort(t, server.URL+"/aborted.json", "partial")
+	mutex.Lock()
+	if _, ok := objects["/aborted.json"]; ok {
+		t.Errorf("got aborted object %q, want none", objects["/aborted.json"])
+	}
+	mutex.Unlock()
+	if _, err := storage.Open(
+		context.Background(), server.URL+"/missing.json",
+	); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("got error %v opening a missing object, want %v",
+			err, fs.ErrNotExist)
+	}
+}
+
+// objects holds the objects of the fake servers by key.
+type objects struct {
+	mutex sync.Mutex
+	data  map[string][]byte
+}
+
+func (o *objects) put(key string, req *http.Request) error {
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.data == nil {
+		o.data = map[string][]byte{}
+	}
+	o.data[key] = data
+	return nil
+}
+
+func (o *objects) get(key string, w http.ResponseWriter, req *http.Request) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	data, ok := o.data[key]
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	_, _ = w.Write(data)
+}
+
+func TestS3(t *testing.T) {
+	store := &objects{}
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			if !strings.HasPrefix(req.Header.Get("Authorization"),
+				"AWS4-HMAC-SHA256 Credential=id/") {
+				http.Error(w, "unsigned request", http.StatusForbidden)
+				return
+			}
+			switch req.Method {
+			case http.MethodPut:
+				if err := store.put(req.URL.EscapedPath(), req); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+				}
+			case http.MethodGet:
+				store.get(req.URL.EscapedPath(), w, req)
+			}
+		},
+	))
+	defer server.Close()
+	storage.Register("s3", storage.S3(storage.S3Config{
+		Region:          "eu-west-1",
+		Endpoint:        server.URL,
+		AccessKeyID:     "id",
+		SecretAccessKey: "secret",
+	}))
+
+	write(t, "s3://bucket/dir/object 1.json", "s3")
+	if got := read(t, "s3://bucket/dir/object 1.json"); got != "s3" {
+		t.Errorf("got %q, want %q", got, "s3")
+	}
+	if _, ok := store.data["/bucket/dir/object%201.json"]; !ok {
+		t.Errorf("got objects %v, want path-style key", store.data)
+	}
+	abort(t, "s3://bucket/aborted.json", "partial")
+	store.mutex.Lock()
+	if _, ok := store.data["/bucket/aborted.json"]; ok {
+		t.Errorf("got aborted object, want none")
+	}
+	store.mutex.Unlock()
+	if _, err := storage.Open(
+		context.Background(), "s3://bucket/missing.json",
+	); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("got error %v opening a missing object, want %v",
+			err, fs.ErrNotExist)
+	}
+}
+
+func TestGCS(t *testing.T) {
+	store := &objects{}
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			if req.Header.Get("Authorization") != "Bearer token" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			switch req.Method {
+			case http.MethodPost:
+				bucket := strings.TrimSuffix(strings.TrimPrefix(
+					req.URL.Path, "/upload/storage/v1/b/"), "/o")
+				key := bucket + "/" + req.URL.Query().Get("name")
+				if err := store.put(key, req); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+				}
+			case http.MethodGet:
+				// the object is a single escaped segment of the path.
+				bucket, object, _ := strings.Cut(strings.TrimPrefix(
+					req.URL.EscapedPath(), "/storage/v1/b/"), "/o/")
+				if strings.Contains(object, "/") {
+					http.Error(w, "unescaped object", http.StatusBadRequest)
+					return
+				}
+				object, err := url.PathUnescape(object)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				store.get(bucket+"/"+object, w, req)
+			}
+		},
+	))
+	defer server.Close()
+	storage.Register("gs", storage.GCS(storage.GCSConfig{
+		Endpoint: server.URL,
+		Token: func(context.Context) (string, error) {
+			return "token", nil
+		},
+	}))
+
+	write(t, "gs://bucket/dir/object.json", "gcs")
+	if got := read(t, "gs://bucket/dir/object.json"); got != "gcs" {
+		t.Errorf("got %q, want %q", got, "gcs")
+	}
+	if _, err := storage.Open(
+		context.Background(), "gs://bucket/missing.json",
+	); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("got error %v opening a missing object, want %v",
+			err, fs.ErrNotExist)
+	}
+}
+
+func TestUnregisteredScheme(t *testing.T) {
+	if _, err := storage.Open(context.Background(), "unknown://x"); err == nil {
+		t.Error("expected error for unregistered scheme")
+	}
+}
@@ -0,0 +1,232 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"runtime/metrics"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nextmv-io/sdk/run/schema"
+	"github.com/nextmv-io/sdk/run/statistics"
+)
+
+// Termination is the reason a runner stopped a run early. It is the cause of
+// the cancelled run context, see context.Cause, and it is reported in the run
+// statistics of schema.Output solutions.
+type Termination string
+
+// Reasons for a runner to stop a run early.
+const (
+	// TerminationMemoryLimit means the heap exceeded the memory limit.
+	TerminationMemoryLimit Termination = "memory_limit"
+	// TerminationSolutionLimit means the algorithm returned the max number of
+	// solutions.
+	TerminationSolutionLimit Termination = "solution_limit"
+	// TerminationCancelled means the run was cancelled on request.
+	TerminationCancelled Termination = "cancelled"
+)
+
+func (t Termination) Error() string {
+	return "run terminated: " + strings.ReplaceAll(string(t), "_", " ")
+}
+
+// terminationOf returns the reason the runner stopped the run of the given
+// context, if it did.
+func terminationOf(ctx context.Context) (Termination, bool) {
+	var termination Termination
+	ok := errors.As(context.Cause(ctx), &termination)
+	return termination, ok
+}
+
+type terminationRecordKey struct{}
+
+// terminationRecord is the reason the runner stopped a run, if it did.
+type terminationRecord struct {
+	termination Termination
+	terminated  bool
+}
+
+// withTerminationRecord returns a context the runner records the reason it
+// stopped the run in, once the run is done.
+func withTerminationRecord(
+	ctx context.Context,
+) (context.Context, *terminationRecord) {
+	record := &terminationRecord{}
+	return context.WithValue(ctx, terminationRecordKey{}, record), record
+}
+
+// recordTermination records the reason the runner stopped the run of the
+// context, if the context has a record.
+func recordTermination(ctx context.Context) {
+	if record, ok := ctx.Value(terminationRecordKey{}).(*terminationRecord); ok {
+		record.termination, record.terminated = terminationOf(ctx)
+	}
+}
+
+// isTerminationError reports whether err is the algorithm reacting to the
+// runner stopping the run, rather than a failure of the algorithm.
+func isTerminationError(ctx context.Context, err error) bool {
+	if _, ok := terminationOf(ctx); !ok {
+		return false
+	}
+	return errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.Cause(ctx))
+}
+
+// markTermination records the reason the runner stopped the run in the run
+// statistics of the solution, if the solution is a schema.Output.
+func markTermination[Solution any](
+	ctx context.Context, solution Solution,
+) Solution {
+	termination, ok := terminationOf(ctx)
+	if !ok {
+		return solution
+	}
+	return updateRunStatistics(solution, func(run *statistics.Run) {
+		run.Termination = string(termination)
+	})
+}
+
+// updateRunStatistics applies the update to the run statistics of the
+// solution, if the solution is a schema.Output.
+func updateRunStatistics[Solution any](
+	solution Solution, update func(*statistics.Run),
+) Solution {
+	switch output := any(solution).(type) {
+	case schema.Output:
+		setRunStatistics(&output, update)
+		if updated, ok := any(output).(Solution); ok {
+			return updated
+		}
+	case *schema.Output:
+		if output != nil {
+			setRunStatistics(output, update)
+		}
+	}
+	return solution
+}
+
+func setRunStatistics(output *schema.Output, update func(*statistics.Run)) {
+	// copy the statistics so solutions shared with the algorithm are not
+	// modified
+	stats := statistics.NewStatistics()
+	if output.Statistics != nil {
+		*stats = *output.Statistics
+	}
+	run := &statistics.Run{}
+	if stats.Run != nil {
+		*run = *stats.Run
+	}
+	update(run)
+	stats.Run = run
+	output.Statistics = stats
+}
+
+// memoryLimit returns the memory limit of the runs, 0 means no limit.
+func memoryLimit(runnerConfig any) int64 {
+	if limiter, ok := runnerConfig.(ResourceLimiter); ok {
+		return limiter.MemoryLimit()
+	}
+	return 0
+}
+
+// memorySampleInterval is the interval in which the heap is sampled to
+// enforce the memory limit.
+const memorySampleInterval = 50 * time.Millisecond
+
+// memory enforces the memory limit of all runs of the process.
+var memory = &memoryWatcher{runs: map[uint64]*watchedRun{}}
+
+// memoryWatcher enforces the memory limit on the heap of the whole process,
+// which is shared by all the runs of the process. It samples the heap while
+// runs are watched and cancels every run whose limit the live heap exceeds
+// with TerminationMemoryLimit.
+type memoryWatcher struct {
+	mutex sync.Mutex
+	runs  map[uint64]*watchedRun
+	next  uint64
+	// done stops the sampling once no run is watched.
+	done chan struct{}
+}
+
+type watchedRun struct {
+	limit     int64
+	cancel    context.CancelCauseFunc
+	cancelled bool
+}
+
+// watch watches the heap for the run until the returned function is called.
+func (w *memoryWatcher) watch(
+	limit int64, cancel context.CancelCauseFunc,
+) (stop func()) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.next++
+	id := w.next
+	w.runs[id] = &watchedRun{limit: limit, cancel: cancel}
+	if len(w.runs) == 1 {
+		w.done = make(chan struct{})
+		go w.sample(w.done)
+	}
+	return func() {
+		w.mutex.Lock()
+		defer w.mutex.Unlock()
+		delete(w.runs, id)
+		if len(w.runs) == 0 {
+			close(w.done)
+		}
+	}
+}
+
+func (w *memoryWatcher) sample(done <-chan struct{}) {
+	ticker := time.NewTicker(memorySampleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			w.enforce()
+		}
+	}
+}
+
+// enforce cancels the runs whose limit the live heap exceeds.
+func (w *memoryWatcher) enforce() {
+	w.mutex.Lock()
+	lowest := int64(0)
+	for _, run := range w.runs {
+		if !run.cancelled && (lowest == 0 || run.limit < lowest) {
+			lowest = run.limit
+		}
+	}
+	w.mutex.Unlock()
+	if lowest == 0 || heapObjects() <= uint64(lowest) {
+		return
+	}
+	// the sampled heap includes garbage that is not swept yet, so only the
+	// heap that is still live after a collection counts.
+	runtime.GC()
+	live := heapObjects()
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	for _, run := range w.runs {
+		if !run.cancelled && live > uint64(run.limit) {
+			run.cancel(TerminationMemoryLimit)
+			run.cancelled = true
+		}
+	}
+}
+
+// heapObjects returns the bytes of the heap objects of the process.
+func heapObjects() uint64 {
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
@@ -0,0 +1,19 @@
+CHECKPOINT=$(mktemp -d)/checkpoint.json
+go run main.go -runner.input.path input.json -runner.output.solutions all \
+  -runner.checkpoint.path $CHECKPOINT -fail 3 2>&1 | sed 's/^.*failed/failed/'
+jq -c 'del(.time)' $CHECKPOINT
+go run main.go -runner.input.path input.json -runner.output.solutions all \
+  -runner.checkpoint.path $CHECKPOINT | jq -c .
+jq -c 'del(.time)' $CHECKPOINT
+# a finished run is not resumed
+go run main.go -runner.input.path input.json -runner.output.solutions all \
+  -runner.checkpoint.path $CHECKPOINT | jq -c .
+# another input does not resume from the checkpoint
+go run main.go -runner.input.path other.json -runner.output.solutions all \
+  -runner.checkpoint.path $CHECKPOINT | jq -c .
+# file:// URIs are local files, which are replaced without leftovers
+go run main.go -runner.input.path input.json -runner.output.solutions all \
+  -runner.checkpoint.path file://$CHECKPOINT -fail 2 2>&1 | sed 's/^.*failed/failed/'
+ls $(dirname $CHECKPOINT)
+jq -c 'del(.time)' $CHECKPOINT
+rm -r $(dirname $CHECKPOINT)
@@ -0,0 +1,57 @@
+// package main holds the implementation of a resumable runner example.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/nextmv-io/sdk/run"
+)
+
+func main() {
+	err := run.NewCLIRunner(algorithm).Run(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+type input struct {
+	Target int `json:"target"`
+}
+
+type option struct {
+	Fail int `json:"fail" usage:"Value after which the run fails, 0 to never fail."`
+}
+
+type output struct {
+	Value int `json:"value"`
+	// Resumed is the state the run was resumed from.
+	Resumed string `json:"resumed,omitempty"`
+}
+
+// algorithm counts up to the target, continuing from the checkpoint of a
+// previous run.
+func algorithm(
+	ctx context.Context, input input, opts option, solutions chan<- output,
+) error {
+	value := 0
+	resumed := ""
+	if solution, ok := run.InitialSolution[output](ctx); ok {
+		value = solution.Value
+	}
+	if state, ok := run.CheckpointState(ctx); ok {
+		resumed = string(state)
+	}
+	for value < input.Target {
+		value++
+		run.SetCheckpointState(ctx, []byte("step "+strconv.Itoa(value)))
+		solutions <- output{Value: value, Resumed: resumed}
+		if value == opts.Fail {
+			return errors.New("failed after " + fmt.Sprint(value))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+sleep 0.5
+go run main.go -runner.http.cache.size 10 -runner.limits.solutions 2 > /dev/null 2>&1 &
+sleep 3.5
+PID2=$(lsof -i -P | grep LISTEN | grep :9006 | tr -s ' ' | cut -d ' ' -f 2)
+# a run stopped at the solution limit is not cached.
+for i in 1 2; do
+	curl -s -o /dev/null -D - -X POST "http://localhost:9006?solutions=2" \
+		-d '{"message":"Hello"}' | grep -i x-cache | tr -d "\r"
+done
+# a completed run is cached.
+for i in 1 2; do
+	curl -s -o /dev/null -D - -X POST "http://localhost:9006?solutions=1" \
+		-d '{"message":"Hello"}' | grep -i x-cache | tr -d "\r"
+done
+# multipart requests are cached, although their boundaries differ.
+for i in 1 2; do
+	curl -s -o /dev/null -D - -X POST "http://localhost:9006?solutions=1" \
+		-F 'input={"message":"Hi"}' | grep -i x-cache | tr -d "\r"
+done
+kill $PID2 > /dev/null 2>&1
+exit 0
@@ -0,0 +1,55 @@
+// package main holds the implementation of a cached runner example.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/nextmv-io/sdk/run"
+	"github.com/nextmv-io/sdk/run/schema"
+)
+
+func main() {
+	err := run.NewHTTPRunner(algorithm,
+		// listen on port 9006
+		run.SetAddr[input, option, schema.Output](":9006"),
+		// override the default logger
+		run.SetLogger[input, option, schema.Output](
+			log.New(os.Stdout, "[demo] - ", log.LstdFlags),
+		),
+	).Run(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+type input struct {
+	Message string `json:"message" usage:"Message to print."`
+}
+
+type option struct {
+	Solutions int `json:"solutions" default:"1" usage:"Number of solutions."`
+}
+
+type output struct {
+	Message string `json:"message"`
+}
+
+// algorithm returns the given number of solutions, unless the run is stopped
+// before.
+func algorithm(
+	ctx context.Context,
+	input input,
+	opts option,
+	solutions chan<- schema.Output,
+) error {
+	for i := 0; i < opts.Solutions; i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case solutions <- schema.NewOutput(opts, output{Message: input.Message}):
+		}
+	}
+	return nil
+}
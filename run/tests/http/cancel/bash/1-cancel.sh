@@ -0,0 +1,12 @@
+sleep 0.5
+go run main.go > /dev/null 2>&1 &
+sleep 3.5
+PID2=$(lsof -i -P | grep LISTEN | grep :9004 | tr -s ' ' | cut -d ' ' -f 2)
+ID=$(curl -s -X POST "http://localhost:9004?duration=10000000000" -d '{"message":"Hello"}')
+sleep 0.5
+curl -s -o /dev/null -w "%{http_code}\n" -X POST "http://localhost:9004/runs/$ID/cancel"
+sleep 0.5
+jq -c '[.solutions[0], .statistics.run.termination]' callback.txt
+curl -s -w "%{http_code}\n" -X POST "http://localhost:9004/runs/$ID/cancel"
+kill $PID2 > /dev/null 2>&1
+exit 0
@@ -0,0 +1,13 @@
+sleep 0.5
+go run main.go > /dev/null 2>&1 &
+sleep 3.5
+PID2=$(lsof -i -P | grep LISTEN | grep :9004 | tr -s ' ' | cut -d ' ' -f 2)
+ID=$(curl -s -X POST "http://localhost:9004?duration=2000000000" -d '{"message":"Hello"}')
+sleep 0.5
+curl -s "http://localhost:9004/runs/$ID" | jq -c '[.status, .progress.fraction, .progress.message, .progress.eta > 0]'
+curl -s -N "http://localhost:9004/runs/$ID/progress" | sed -E 's/"elapsed":[^,]*,"eta":[^}]*/"elapsed":0,"eta":0/'
+sleep 0.5
+jq -c '.statistics.run.progress' callback.txt
+curl -s -w "%{http_code}\n" "http://localhost:9004/runs/$ID"
+kill $PID2 > /dev/null 2>&1
+exit 0
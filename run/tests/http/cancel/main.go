@@ -0,0 +1,89 @@
+// package main holds the implementation of a cancellable runner example.
+package main
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/nextmv-io/sdk/run"
+	"github.com/nextmv-io/sdk/run/schema"
+)
+
+func main() {
+	// start a callback server listening on port 8081
+	go func() {
+		handler := http.HandlerFunc(callback)
+		http.Handle("/callback", handler)
+		err := http.ListenAndServe(":8081", nil)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	err := run.NewHTTPRunner(algorithm,
+		// listen on port 9004
+		run.SetAddr[input, option, schema.Output](":9004"),
+		// override the default logger
+		run.SetLogger[input, option, schema.Output](
+			log.New(os.Stdout, "[demo] - ", log.LstdFlags),
+		),
+		// send solutions to the callback URL instead of returning them directly
+		run.SetHTTPRequestHandler[input, option, schema.Output](
+			run.AsyncHTTPRequestHandler(
+				run.CallbackURL("http://localhost:8081/callback"),
+			),
+		),
+	).Run(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func callback(_ http.ResponseWriter, r *http.Request) {
+	var b bytes.Buffer
+	_, err := b.ReadFrom(r.Body)
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = os.WriteFile("callback.txt", b.Bytes(), 0o644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+type input struct {
+	Message string `json:"message" usage:"Message to print."`
+}
+
+type option struct {
+	Duration time.Duration `json:"duration" default:"1s" usage:"Sleep duration."`
+}
+
+type output struct {
+	Message string `json:"message"`
+}
+
+// algorithm returns a first solution immediately and an improved one after the
+// duration, unless the run is cancelled before. It reports its progress after
+// each solution.
+func algorithm(
+	ctx context.Context,
+	input input,
+	opts option,
+	solutions chan<- schema.Output,
+) error {
+	solutions <- schema.NewOutput(opts, output{Message: input.Message})
+	run.Report(ctx, 0.5, "first solution")
+	select {
+	case <-time.After(opts.Duration):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	solutions <- schema.NewOutput(opts, output{Message: input.Message + " World!"})
+	run.Report(ctx, 1, "improved solution")
+	return nil
+}
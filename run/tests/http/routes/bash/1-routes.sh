@@ -0,0 +1,16 @@
+sleep 0.5
+SUM_FACTOR=3 FACTOR=5 go run main.go > /dev/null 2>&1 &
+sleep 3.5
+PID2=$(lsof -i -P | grep LISTEN | grep :9005 | tr -s ' ' | cut -d ' ' -f 2)
+URL="http://localhost:9005"
+curl -s $URL/health | jq
+curl -s -X POST $URL -d '{"message":"Hello"}' | jq .solutions
+curl -s -X POST "$URL/solve/sum?factor=2" -d '{"values":[1,2,3]}' | jq
+# the default factor is taken from SUM_FACTOR, not FACTOR.
+curl -s -X POST "$URL/solve/sum" -d '{"values":[1,2,3]}' | jq -c
+curl -s -o /dev/null -w "%{http_code}\n" -X POST $URL/solve/unknown -d '{}'
+curl -s -X POST $URL/solve/sum -d '{"values":"x"}'
+curl -s $URL/openapi.json | jq '.paths | keys, (.["/solve/sum"].post.parameters[1])'
+curl -s $URL/openapi.json | jq '.components.schemas | keys'
+kill $PID2 > /dev/null 2>&1
+exit 0
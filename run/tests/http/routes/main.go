@@ -0,0 +1,67 @@
+// package main holds the implementation of a runner serving several
+// algorithms.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/nextmv-io/sdk/run"
+	"github.com/nextmv-io/sdk/run/schema"
+)
+
+func main() {
+	err := run.HTTP(greet,
+		// listen on port 9005
+		run.SetAddr[greeting, greetOption, schema.Output](":9005"),
+		// override the default logger
+		run.SetLogger[greeting, greetOption, schema.Output](
+			log.New(os.Stdout, "[demo] - ", log.Lshortfile),
+		),
+		// serve sum at /solve/sum
+		run.AddRoute[greeting, greetOption, schema.Output](
+			run.NewRoute("sum", sum),
+		),
+	).Run(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+type greeting struct {
+	Message string `json:"message"`
+}
+
+type greetOption struct {
+	Suffix string `json:"suffix" default:" World!" usage:"Suffix of the message."`
+}
+
+func greet(
+	_ context.Context, input greeting, opts greetOption,
+) (schema.Output, error) {
+	return schema.NewOutput(opts, greeting{Message: input.Message + opts.Suffix}), nil
+}
+
+type numbers struct {
+	Values []int `json:"values"`
+}
+
+type sumOption struct {
+	Factor int `json:"factor" default:"1" usage:"Factor of the sum."`
+}
+
+type total struct {
+	Sum int `json:"sum"`
+}
+
+func sum(
+	_ context.Context, input numbers, opts sumOption, solutions chan<- total,
+) error {
+	result := 0
+	for _, value := range input.Values {
+		result += value
+	}
+	solutions <- total{Sum: result * opts.Factor}
+	return nil
+}
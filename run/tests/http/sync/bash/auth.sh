@@ -0,0 +1,26 @@
+sleep 0.5
+go run main.go -runner.http.auth.keys keys.json > /dev/null 2>&1 &
+sleep 3.5
+PID2=$(lsof -i -P | grep LISTEN | grep :9000 | tr -s ' ' | cut -d ' ' -f 2)
+URL="http://localhost:9000?duration=500000000"
+curl -s -w "%{http_code}\n" -X POST $URL -d '{"message":"Hello"}'
+curl -s -w "%{http_code}\n" -X POST $URL -H 'X-API-Key: wrong' -d '{"message":"Hello"}'
+curl -s -o /dev/null -w "%{http_code}\n" -X POST $URL -H 'X-API-Key: secret' -d '{"message":"Hello"}'
+# the version requires authentication, the health does not
+curl -s -w "%{http_code}\n" http://localhost:9000/version
+curl -s -o /dev/null -w "%{http_code}\n" http://localhost:9000/version -H 'X-API-Key: secret'
+curl -s -o /dev/null -w "%{http_code}\n" http://localhost:9000/health
+# a signed request is accepted once
+TS=$(date +%s)
+BODY='{"message":"Hello"}'
+SIG=$(printf '%s\n%s\n%s\n%s' "$TS" POST "/?duration=500000000" "$BODY" \
+  | openssl dgst -sha256 -hmac secret | sed 's/^.* //')
+curl -s -o /dev/null -w "%{http_code}\n" -X POST $URL -d "$BODY" \
+  -H "X-Timestamp: $TS" -H "X-Signature: client:$SIG"
+curl -s -w "%{http_code}\n" -X POST $URL -d "$BODY" \
+  -H "X-Timestamp: $TS" -H "X-Signature: client:$SIG"
+kill $PID2 > /dev/null 2>&1
+# client certificates require TLS
+go run main.go -runner.http.auth.client_ca keys.json 2>&1 \
+  | sed 's/^.*runner.http.auth/runner.http.auth/'
+exit 0
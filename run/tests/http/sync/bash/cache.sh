@@ -0,0 +1,10 @@
+sleep 0.5
+go run main.go -runner.http.cache.size 1 > /dev/null 2>&1 &
+sleep 3.5
+PID2=$(lsof -i -P | grep LISTEN | grep :9000 | tr -s ' ' | cut -d ' ' -f 2)
+URL="http://localhost:9000?duration=500000000"
+curl -s -D - -o /dev/null -X POST $URL -d '{"message":"Hello"}' | grep X-Cache
+curl -s -D - -o /dev/null -X POST $URL -d '{ "message": "Hello" }' | grep X-Cache
+curl -s -D - -o /dev/null -X POST $URL -H 'Cache-Control: no-cache' -d '{"message":"Hello"}' | grep X-Cache
+kill $PID2 > /dev/null 2>&1
+exit 0
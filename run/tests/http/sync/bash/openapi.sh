@@ -0,0 +1,10 @@
+sleep 0.5
+go run main.go > /dev/null 2>&1 &
+sleep 3.5
+PID2=$(lsof -i -P | grep LISTEN | grep :9000 | tr -s ' ' | cut -d ' ' -f 2)
+URL="http://localhost:9000/openapi.json"
+curl -s $URL | jq '.paths | keys'
+curl -s $URL | jq '.paths["/"].post | .parameters, .requestBody, (.responses | keys)'
+curl -s $URL | jq '.components.schemas.Input'
+kill $PID2 > /dev/null 2>&1
+exit 0
@@ -0,0 +1,9 @@
+sleep 0.5
+go run main.go -runner.http.ratelimit.rate 0.1 > /dev/null 2>&1 &
+sleep 3.5
+PID2=$(lsof -i -P | grep LISTEN | grep :9000 | tr -s ' ' | cut -d ' ' -f 2)
+URL="http://localhost:9000?duration=500000000"
+curl -s -o /dev/null -w "%{http_code}\n" -X POST $URL -d '{"message":"Hello"}'
+curl -s -D - -X POST $URL -d '{"message":"Hello"}' | grep -E "HTTP|Retry-After|exceeded"
+kill $PID2 > /dev/null 2>&1
+exit 0
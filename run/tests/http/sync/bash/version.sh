@@ -0,0 +1,8 @@
+sleep 0.5
+go run . > /dev/null 2>&1 &
+sleep 3.5
+PID2=$(lsof -i -P | grep LISTEN | grep :9000 | tr -s ' ' | cut -d ' ' -f 2)
+curl -s http://localhost:9000/version \
+  | jq -c '{module: .module, version: .version, go: (.go_version | startswith("go")), dependencies: .dependencies}'
+kill $PID2 > /dev/null 2>&1
+exit 0
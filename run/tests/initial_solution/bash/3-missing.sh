@@ -0,0 +1,3 @@
+go run main.go \
+    -runner.input.path input.json \
+    -runner.input.initial_solution missing.json 2>&1 | sed 's/^.*open/open/'
@@ -0,0 +1,36 @@
+// package main holds the implementation of a warm-started runner example.
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/nextmv-io/sdk/run"
+)
+
+func main() {
+	err := run.CLI(algorithm).Run(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+type input struct {
+	Message string `json:"message" usage:"Message to print."`
+}
+
+type option struct{}
+
+type output struct {
+	Messages []string `json:"messages"`
+}
+
+func algorithm(ctx context.Context, input input, _ option) (output, error) {
+	// continue from the initial solution, if one is given
+	solution, ok := run.InitialSolution[output](ctx)
+	if !ok {
+		solution = output{}
+	}
+	solution.Messages = append(solution.Messages, input.Message)
+	return solution, nil
+}
@@ -0,0 +1,4 @@
+go run main.go \
+    -runner.input.path input.json \
+    -runner.output.solutions all \
+    -runner.output.format array | jq -c '.[].statistics.result.value'
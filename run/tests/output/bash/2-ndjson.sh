@@ -0,0 +1,5 @@
+go run main.go \
+    -runner.seed 1 \
+    -runner.input.path input.json \
+    -runner.output.solutions all \
+    -runner.output.format ndjson
@@ -0,0 +1,10 @@
+go run main.go \
+    -runner.seed 1 \
+    -runner.input.path input.json \
+    -runner.output.solutions all \
+    -runner.output.format split \
+    -runner.output.path solutions
+ls solutions
+jq -c '.solutions[] | del(.time)' solutions/manifest.json
+jq -c . solutions/solution-0003.json
+rm -r solutions
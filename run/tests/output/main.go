@@ -0,0 +1,49 @@
+// package main holds the implementation of a runner example returning several
+// solutions.
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/nextmv-io/sdk/run"
+	"github.com/nextmv-io/sdk/run/schema"
+	"github.com/nextmv-io/sdk/run/statistics"
+)
+
+func main() {
+	err := run.NewCLIRunner(algorithm).Run(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+type input struct {
+	Message string `json:"message" usage:"Message to print."`
+}
+
+type option struct {
+	Improvements int `json:"improvements" default:"3" usage:"Number of solutions."`
+}
+
+type output struct {
+	Message string `json:"message"`
+}
+
+// algorithm returns a solution per improvement, each with a lower value.
+func algorithm(
+	_ context.Context,
+	input input,
+	opts option,
+	solutions chan<- schema.Output,
+) error {
+	for i := 0; i < opts.Improvements; i++ {
+		solution := schema.NewOutput(opts, output{Message: input.Message})
+		solution.Version = nil
+		solution.Statistics = statistics.NewStatistics()
+		value := statistics.Float64(opts.Improvements - i)
+		solution.Statistics.Result = &statistics.Result{Value: &value}
+		solutions <- solution
+	}
+	return nil
+}
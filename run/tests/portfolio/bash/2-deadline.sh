@@ -0,0 +1,2 @@
+go run main.go -runner.input.path input.json -runner.output.solutions all \
+  -optimal 0 -duration 500ms | jq -c .
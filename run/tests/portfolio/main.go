@@ -0,0 +1,80 @@
+// package main holds the implementation of a portfolio runner example.
+package main
+
+import (
+	"cmp"
+	"context"
+	"log"
+	"time"
+
+	"github.com/nextmv-io/sdk/run"
+)
+
+func main() {
+	err := run.NewCLIRunner(algorithm).Run(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+type input struct {
+	// Values are the values of the solutions the heuristic finds.
+	Values []int `json:"values"`
+}
+
+type option struct {
+	Duration time.Duration `json:"duration" default:"10s" usage:"Max duration of the run."`
+	Optimal  int           `json:"optimal" default:"7" usage:"Value the exact algorithm proves optimal, 0 to never prove."`
+}
+
+type output struct {
+	Value  int    `json:"value"`
+	Solver string `json:"solver"`
+}
+
+// algorithm races the heuristic and the exact algorithm until the duration
+// passes.
+func algorithm(
+	ctx context.Context, input input, opts option, solutions chan<- output,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, opts.Duration)
+	defer cancel()
+	// the exact algorithm waits for the heuristic, so the output is stable.
+	heuristicDone := make(chan struct{})
+	return run.Portfolio(
+		func(a, b output) int { return cmp.Compare(a.Value, b.Value) },
+		heuristic(heuristicDone),
+		exact(heuristicDone),
+	)(ctx, input, opts, solutions)
+}
+
+// heuristic sends the values of the input as solutions, then waits to be
+// cancelled.
+func heuristic(done chan<- struct{}) run.Algorithm[input, option, output] {
+	return func(
+		ctx context.Context, input input, _ option, solutions chan<- output,
+	) error {
+		for _, value := range input.Values {
+			solutions <- output{Value: value, Solver: "heuristic"}
+		}
+		close(done)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+}
+
+// exact sends the optimal value and proves it, unless it is 0.
+func exact(heuristicDone <-chan struct{}) run.Algorithm[input, option, output] {
+	return func(
+		ctx context.Context, _ input, opts option, solutions chan<- output,
+	) error {
+		<-heuristicDone
+		if opts.Optimal == 0 {
+			<-ctx.Done()
+			return nil
+		}
+		solutions <- output{Value: opts.Optimal, Solver: "exact"}
+		run.ProvenOptimal(ctx)
+		return nil
+	}
+}
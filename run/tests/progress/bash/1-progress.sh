@@ -0,0 +1,4 @@
+go run main.go -runner.input.path input.json -runner.seed 1 2>&1 >/dev/null \
+  | sed -E 's/^.* progress/progress/; s/\(eta [^)]*\)/(eta -)/'
+go run main.go -runner.input.path input.json -runner.seed 1 2>/dev/null \
+  | jq -c .statistics.run.progress
@@ -0,0 +1,42 @@
+// package main holds the implementation of a runner example reporting its
+// progress.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/nextmv-io/sdk/run"
+	"github.com/nextmv-io/sdk/run/schema"
+)
+
+func main() {
+	err := run.CLI(algorithm).Run(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+type input struct {
+	Steps int `json:"steps"`
+}
+
+type option struct{}
+
+type output struct {
+	Steps int `json:"steps"`
+}
+
+func algorithm(
+	ctx context.Context, input input, opts option,
+) (schema.Output, error) {
+	for step := 1; step <= input.Steps; step++ {
+		// report the fraction of the steps done
+		run.Report(ctx, float64(step)/float64(input.Steps),
+			fmt.Sprintf("step %d of %d", step, input.Steps))
+	}
+	solution := schema.NewOutput(opts, output{Steps: input.Steps})
+	solution.Version = nil
+	return solution, nil
+}
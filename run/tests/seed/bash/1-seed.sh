@@ -0,0 +1 @@
+go run main.go -runner.input.path input.json -runner.seed 42 | jq -c .
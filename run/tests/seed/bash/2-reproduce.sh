@@ -0,0 +1,5 @@
+go run main.go -runner.input.path input.json > first.json
+SEED=$(jq .statistics.run.seed first.json)
+go run main.go -runner.input.path input.json -runner.seed $SEED > second.json
+cmp first.json second.json && echo "reproduced"
+rm first.json second.json
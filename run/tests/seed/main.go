@@ -0,0 +1,42 @@
+// package main holds the implementation of a randomized runner example.
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/nextmv-io/sdk/run"
+	"github.com/nextmv-io/sdk/run/schema"
+)
+
+func main() {
+	err := run.CLI(algorithm).Run(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+type input struct {
+	Count int `json:"count"`
+}
+
+type option struct{}
+
+type output struct {
+	Numbers []int `json:"numbers"`
+}
+
+func algorithm(
+	ctx context.Context, input input, opts option,
+) (schema.Output, error) {
+	// draw from the random number generator of the run, so the run can be
+	// reproduced with the seed recorded in the output
+	random := run.NewRand(ctx)
+	numbers := make([]int, input.Count)
+	for i := range numbers {
+		numbers[i] = random.Intn(100)
+	}
+	solution := schema.NewOutput(opts, output{Numbers: numbers})
+	solution.Version = nil
+	return solution, nil
+}
@@ -0,0 +1,12 @@
+go run main.go \
+    -runner.input.path input.json.zst \
+    -duration 1s | jq -c .solutions
+go run main.go \
+    -runner.input.path input.json \
+    -runner.limits.input.size 10 2>&1 | sed 's/^.*input too/input too/'
+go run main.go \
+    -runner.input.path input.json.zst \
+    -runner.limits.input.decompressed 10 2>&1 | sed 's/^.*input too/input too/'
+go run main.go \
+    -runner.input.path input.json \
+    -runner.limits.input.decompressed 10 2>&1 | sed 's/^.*input too/input too/'
@@ -0,0 +1,6 @@
+go run main.go \
+    -runner.input.path input.json \
+    -runner.output.path output.json.gz \
+    -duration 1s
+gzip -dc output.json.gz | jq -c .solutions
+rm output.json.gz
@@ -29,6 +29,7 @@ func TestGolden(t *testing.T) {
 				{Key: ".solutions[0].statistics.time.elapsed", Replacement: golden.StableDuration},
 				{Key: ".solutions[0].statistics.time.elapsed_seconds", Replacement: golden.StableFloat},
 				{Key: ".solutions[0].statistics.time.start", Replacement: golden.StableTime},
+				{Key: "$.statistics.run.seed", Replacement: golden.StableFloat},
 			},
 		},
 	)
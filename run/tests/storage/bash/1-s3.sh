@@ -0,0 +1,6 @@
+go run main.go \
+    -runner.seed 1 \
+    -runner.input.path s3://inputs/input.json \
+    -runner.output.path s3://outputs/output.json
+jq -c . data/outputs/output.json
+rm -r data/outputs
@@ -0,0 +1,2 @@
+go run main.go \
+    -runner.input.path ftp://inputs/input.json 2>&1 | sed 's/^.*no storage/no storage/'
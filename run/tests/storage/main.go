@@ -0,0 +1,38 @@
+// package main holds the implementation of a runner example reading from and
+// writing to object storage.
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/nextmv-io/sdk/run"
+	"github.com/nextmv-io/sdk/run/schema"
+	"github.com/nextmv-io/sdk/run/storage"
+)
+
+func main() {
+	// serve s3:// URIs from the data directory, instead of a real bucket
+	storage.Register("s3", storage.Dir("data"))
+
+	err := run.CLI(algorithm).Run(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+type input struct {
+	Message string `json:"message" usage:"Message to print."`
+}
+
+type option struct{}
+
+type output struct {
+	Message string `json:"message"`
+}
+
+func algorithm(_ context.Context, input input, opts option) (schema.Output, error) {
+	solution := schema.NewOutput(opts, output{Message: input.Message + " World!"})
+	solution.Version = nil
+	return solution, nil
+}
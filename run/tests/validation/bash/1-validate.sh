@@ -0,0 +1,28 @@
+# solutions are not validated by default
+go run main.go -runner.input.path input.json -runner.output.solutions all \
+  | jq -c .
+# invalid solutions are logged, but still written
+OUTPUT=$(mktemp)
+go run main.go -runner.input.path input.json -runner.output.solutions all \
+  -runner.output.path $OUTPUT -runner.output.validation warn 2>&1 \
+  | sed 's/^.*invalid/invalid/'
+jq -c . $OUTPUT
+rm $OUTPUT
+# the first invalid solution fails the run
+OUTPUT=$(mktemp)
+go run main.go -runner.input.path input.json -runner.output.solutions all \
+  -runner.output.path $OUTPUT -runner.output.validation fail 2>&1 \
+  | sed 's/^.*invalid/invalid/'
+jq -c . $OUTPUT
+rm $OUTPUT
+# the schema is read from a file
+go run main.go -runner.input.path input.json -runner.output.solutions all \
+  -runner.output.validation fail -runner.output.schema schema.json 2>&1 \
+  | sed 's/^.*invalid/invalid/'
+# an invalid schema is reported
+SCHEMA=$(mktemp)
+echo '{"type": 1}' > $SCHEMA
+go run main.go -runner.input.path input.json \
+  -runner.output.validation fail -runner.output.schema $SCHEMA 2>&1 \
+  | sed 's/^.*loading/loading/'
+rm $SCHEMA
@@ -0,0 +1,37 @@
+// package main holds the implementation of a runner example validating its
+// solutions.
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/nextmv-io/sdk/run"
+)
+
+func main() {
+	err := run.NewCLIRunner(algorithm).Run(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+type input struct {
+	Start int `json:"start"`
+	Stop  int `json:"stop"`
+}
+
+type output struct {
+	Value int `json:"value" minimum:"0"`
+}
+
+// algorithm counts down from the start to the stop, which makes the value
+// invalid once it is negative.
+func algorithm(
+	_ context.Context, input input, _ struct{}, solutions chan<- output,
+) error {
+	for value := input.Start; value >= input.Stop; value-- {
+		solutions <- output{Value: value}
+	}
+	return nil
+}
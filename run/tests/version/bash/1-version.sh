@@ -0,0 +1,7 @@
+# the version is printed instead of running the algorithm
+go run . -runner.version -runner.input.path input.json \
+  | jq -c '{module: .module, version: .version,
+    go: (.go_version | startswith("go")), dependencies: .dependencies}'
+# the build time is set when linking
+go run -ldflags "-X github.com/nextmv-io/sdk/run/schema.BuildTime=2024-01-01T00:00:00Z" \
+  . -runner.version | jq -c '{build_time}'
@@ -0,0 +1,31 @@
+// package main holds the implementation of a runner example reporting its
+// build information.
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/nextmv-io/sdk/run"
+	"github.com/nextmv-io/sdk/run/schema"
+)
+
+func main() {
+	// report the version of another dependency, too.
+	schema.AddKnownDependency("huma", "github.com/danielgtaylor/huma")
+	err := run.NewCLIRunner(algorithm).Run(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+type output struct {
+	Message string `json:"message"`
+}
+
+func algorithm(
+	_ context.Context, _ struct{}, _ struct{}, solutions chan<- output,
+) error {
+	solutions <- output{Message: "hello"}
+	return nil
+}
@@ -0,0 +1,2 @@
+echo '{"jsonrpc":"2.0","id":1,"method":"solve","params":{"input":{"message":"Hello"},"options":{"duration":1000000}}}' | \
+    go run main.go -runner.seed 1
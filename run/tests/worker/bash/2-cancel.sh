@@ -0,0 +1,7 @@
+# The response to the cancel request and the cancelled solve request race, so
+# the output is sorted.
+(
+    echo '{"jsonrpc":"2.0","id":"a","method":"solve","params":{"input":{"message":"Hello"},"options":{"duration":60000000000}}}'
+    sleep 1
+    echo '{"jsonrpc":"2.0","id":2,"method":"cancel","params":{"id":"a"}}'
+) | go run main.go -runner.seed 1 | LC_ALL=C sort
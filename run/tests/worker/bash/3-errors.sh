@@ -0,0 +1,6 @@
+(
+    echo '{"jsonrpc":"2.0","id":1,"method":'
+    echo '{"jsonrpc":"2.0","id":2,"method":"optimize"}'
+    echo '{"jsonrpc":"2.0","id":3,"method":"solve","params":{}}'
+    echo '{"jsonrpc":"2.0","id":4,"method":"solve","params":{"input":{"msg":"Hello"}}}'
+) | go run main.go
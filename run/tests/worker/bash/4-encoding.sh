@@ -0,0 +1,3 @@
+# notifications and results are encoded with the configured encoder
+echo '{"jsonrpc":"2.0","id":1,"method":"solve","params":{"input":{"message":"Hello"},"options":{"duration":1000000}}}' | \
+    WORKER_ENCODING=text go run main.go -runner.seed 1
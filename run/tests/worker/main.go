@@ -0,0 +1,71 @@
+// package main holds the implementation of a worker runner example.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/nextmv-io/sdk/run"
+	"github.com/nextmv-io/sdk/run/schema"
+)
+
+func main() {
+	var options []run.RunnerOption[
+		run.WorkerRunnerConfig, input, option, schema.Output,
+	]
+	// solutions and results are encoded with the configured encoder.
+	if os.Getenv("WORKER_ENCODING") == "text" {
+		options = append(options, run.Encode[run.WorkerRunnerConfig, input](
+			run.GenericEncoder[schema.Output, option](textEncoder{}),
+		))
+	}
+	err := run.NewWorkerRunner(algorithm, options...).Run(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+type input struct {
+	Message string `json:"message" usage:"Message to print."`
+}
+
+type option struct {
+	Duration time.Duration `json:"duration" default:"1s" usage:"Sleep duration."`
+}
+
+type output struct {
+	Message string `json:"message"`
+}
+
+func algorithm(
+	ctx context.Context,
+	input input,
+	opts option,
+	solutions chan<- schema.Output,
+) error {
+	solutions <- schema.NewOutput(opts, output{Message: input.Message})
+	select {
+	// sleep for the specified duration, 1s by default as defined via go tags
+	case <-time.After(opts.Duration):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	solutions <- schema.NewOutput(opts, output{Message: input.Message + " World!"})
+	return nil
+}
+
+// textEncoder encodes the messages of the solutions as text.
+type textEncoder struct{}
+
+func (textEncoder) Encode(w io.Writer, v any) error {
+	for _, solution := range v.(schema.Output).Solutions {
+		if _, err := fmt.Fprintln(w, solution.(output).Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// JSONLines returns a Tracer that writes every ended span as a single line of
+// JSON to the writer. It is safe for concurrent use.
+func JSONLines(w io.Writer) Tracer {
+	return &jsonLinesTracer{writer: w}
+}
+
+// File returns a JSONLines Tracer appending to the file at the given path. The
+// file is created if it does not exist. The tracer implements io.Closer, which
+// closes the file. Spans ended after it is closed are dropped.
+func File(path string) (Tracer, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonLinesTracer{writer: f, closer: f}, nil
+}
+
+type jsonLinesTracer struct {
+	mu     sync.Mutex
+	writer io.Writer
+	// closer is the file of a File tracer, it is nil once closed.
+	closer io.Closer
+}
+
+// Close closes the file of a File tracer. It does nothing for other tracers
+// and if the file is closed already.
+func (t *jsonLinesTracer) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closer == nil {
+		return nil
+	}
+	err := t.closer.Close()
+	t.writer, t.closer = io.Discard, nil
+	return err
+}
+
+func (t *jsonLinesTracer) Start(
+	ctx context.Context,
+	name string,
+) (context.Context, Span) {
+	s := &jsonLinesSpan{
+		tracer: t,
+		record: record{
+			Name:  name,
+			Start: time.Now(),
+		},
+		spanContext: SpanContext{SpanID: newSpanID(), Flags: 1},
+	}
+	if parent, ok := SpanContextFromContext(ctx); ok {
+		s.spanContext.TraceID = parent.TraceID
+		s.spanContext.Flags = parent.Flags
+		s.record.ParentSpanID = parent.SpanID.String()
+	} else {
+		s.spanContext.TraceID = newTraceID()
+	}
+	s.record.TraceID = s.spanContext.TraceID.String()
+	s.record.SpanID = s.spanContext.SpanID.String()
+	return ContextWithSpanContext(ctx, s.spanContext), s
+}
+
+func (t *jsonLinesTracer) export(r record) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return
+	}
+	b = append(b, '\n')
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// Tracing must never fail a run, so write errors are dropped.
+	_, _ = t.writer.Write(b)
+}
+
+// record is the JSON representation of an ended span.
+type record struct {
+	Name         string         `json:"name"`
+	TraceID      string         `json:"trace_id"`
+	SpanID       string         `json:"span_id"`
+	ParentSpanID string         `json:"parent_span_id,omitempty"`
+	Start        time.Time      `json:"start"`
+	End          time.Time      `json:"end"`
+	Duration     float64        `json:"duration"`
+	Attributes   map[string]any `json:"attributes,omitempty"`
+	Error        string         `json:"error,omitempty"`
+}
+
+type jsonLinesSpan struct {
+	mu          sync.Mutex
+	tracer      *jsonLinesTracer
+	record      record
+	spanContext SpanContext
+	ended       bool
+}
+
+func (s *jsonLinesSpan) SetAttribute(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.record.Attributes == nil {
+		s.record.Attributes = map[string]any{}
+	}
+	s.record.Attributes[key] = value
+}
+
+func (s *jsonLinesSpan) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.record.Error = err.Error()
+}
+
+func (s *jsonLinesSpan) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.record.End = time.Now()
+	s.record.Duration = s.record.End.Sub(s.record.Start).Seconds()
+	r := s.record
+	s.mu.Unlock()
+	s.tracer.export(r)
+}
+
+func (s *jsonLinesSpan) SpanContext() SpanContext {
+	return s.spanContext
+}
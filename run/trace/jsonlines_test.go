@@ -0,0 +1,44 @@
+package trace_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nextmv-io/sdk/run/trace"
+)
+
+func TestFileClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+	tracer, err := trace.File(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, span := tracer.Start(context.Background(), "before")
+	span.End()
+	closer, ok := tracer.(io.Closer)
+	if !ok {
+		t.Fatalf("tracer %T is not an io.Closer", tracer)
+	}
+	if err := closer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// spans ended after closing are dropped.
+	_, span = tracer.Start(context.Background(), "after")
+	span.End()
+	if err := closer.Close(); err != nil {
+		t.Errorf("closing twice: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], `"name":"before"`) {
+		t.Errorf("got spans %q, want only the one ended before closing", lines)
+	}
+}
@@ -0,0 +1,66 @@
+// Package trace provides a minimal tracing API to instrument the stages of a
+// runner. Spans follow the W3C Trace Context model, so they can be correlated
+// with spans of other services.
+package trace
+
+import "context"
+
+// Tracer starts spans.
+type Tracer interface {
+	// Start starts a new span with the given name. If the context holds a
+	// span, the new span is a child of it. The returned context holds the new
+	// span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single timed operation.
+type Span interface {
+	// SetAttribute sets an attribute of the span.
+	SetAttribute(key string, value any)
+	// RecordError records an error on the span. Nil errors are ignored.
+	RecordError(err error)
+	// End ends the span. Calling End more than once has no effect.
+	End()
+	// SpanContext returns the identifiers of the span.
+	SpanContext() SpanContext
+}
+
+// Noop returns a Tracer that records nothing.
+func Noop() Tracer {
+	return noopTracer{}
+}
+
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, _ string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(string, any) {}
+
+func (noopSpan) RecordError(error) {}
+
+func (noopSpan) End() {}
+
+func (noopSpan) SpanContext() SpanContext {
+	return SpanContext{}
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpanContext returns a copy of the context holding the given span
+// context. Spans started from the returned context become its children.
+func ContextWithSpanContext(
+	ctx context.Context,
+	spanContext SpanContext,
+) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, spanContext)
+}
+
+// SpanContextFromContext returns the span context held by the context, if any.
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	spanContext, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return spanContext, ok && spanContext.IsValid()
+}
@@ -0,0 +1,120 @@
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TraceParentHeader is the W3C Trace Context header propagating the parent
+// span.
+const TraceParentHeader = "traceparent"
+
+// TraceID identifies a trace.
+type TraceID [16]byte
+
+// String returns the hex encoding of the trace ID.
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// SpanID identifies a span.
+type SpanID [8]byte
+
+// String returns the hex encoding of the span ID.
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// SpanContext holds the identifiers of a span that are propagated across
+// process boundaries.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Flags   byte
+}
+
+// IsValid reports whether both the trace ID and the span ID are set.
+func (s SpanContext) IsValid() bool {
+	return s.TraceID != TraceID{} && s.SpanID != SpanID{}
+}
+
+// TraceParent returns the span context formatted as a W3C traceparent header
+// value.
+func (s SpanContext) TraceParent() string {
+	return fmt.Sprintf("00-%s-%s-%02x", s.TraceID, s.SpanID, s.Flags)
+}
+
+// ParseTraceParent parses a W3C traceparent header value, e.g.
+// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01.
+func ParseTraceParent(value string) (SpanContext, error) {
+	var spanContext SpanContext
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return spanContext, fmt.Errorf("invalid traceparent %q", value)
+	}
+	version, err := hex.DecodeString(parts[0])
+	if err != nil || len(version) != 1 || version[0] == 0xff {
+		return spanContext, fmt.Errorf("invalid traceparent version %q", parts[0])
+	}
+	// Version 00 defines exactly four fields, future versions may append
+	// more.
+	if version[0] == 0 && len(parts) != 4 {
+		return spanContext, fmt.Errorf("invalid traceparent %q", value)
+	}
+	if err := decodeHex(parts[1], spanContext.TraceID[:]); err != nil {
+		return spanContext, fmt.Errorf("invalid trace id: %w", err)
+	}
+	if err := decodeHex(parts[2], spanContext.SpanID[:]); err != nil {
+		return spanContext, fmt.Errorf("invalid parent id: %w", err)
+	}
+	var flags [1]byte
+	if err := decodeHex(parts[3], flags[:]); err != nil {
+		return spanContext, fmt.Errorf("invalid trace flags: %w", err)
+	}
+	spanContext.Flags = flags[0]
+	if !spanContext.IsValid() {
+		return spanContext, fmt.Errorf("invalid traceparent %q", value)
+	}
+	return spanContext, nil
+}
+
+// Extract returns a copy of the context holding the span context propagated
+// by the traceparent header, if present and valid.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	spanContext, err := ParseTraceParent(header.Get(TraceParentHeader))
+	if err != nil {
+		return ctx
+	}
+	return ContextWithSpanContext(ctx, spanContext)
+}
+
+// Inject sets the traceparent header from the span context held by the
+// context, if any.
+func Inject(ctx context.Context, header http.Header) {
+	if spanContext, ok := SpanContextFromContext(ctx); ok {
+		header.Set(TraceParentHeader, spanContext.TraceParent())
+	}
+}
+
+func decodeHex(s string, dst []byte) error {
+	if len(s) != hex.EncodedLen(len(dst)) || strings.ToLower(s) != s {
+		return fmt.Errorf("expected %d lowercase hex characters, got %q",
+			hex.EncodedLen(len(dst)), s)
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err
+}
+
+func newTraceID() (id TraceID) {
+	_, _ = rand.Read(id[:])
+	return id
+}
+
+func newSpanID() (id SpanID) {
+	_, _ = rand.Read(id[:])
+	return id
+}
@@ -0,0 +1,126 @@
+package trace_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/nextmv-io/sdk/run/trace"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{
+			name:  "valid",
+			value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		},
+		{
+			name:  "future version with extra field",
+			value: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-ff",
+		},
+		{
+			name:    "extra field in version 00",
+			value:   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-ff",
+			wantErr: true,
+		},
+		{
+			name:    "invalid version",
+			value:   "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			wantErr: true,
+		},
+		{
+			name:    "zero trace id",
+			value:   "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+			wantErr: true,
+		},
+		{
+			name:    "uppercase",
+			value:   "00-4BF92F3577B34DA6A3CE929D0E0E4736-00F067AA0BA902B7-01",
+			wantErr: true,
+		},
+		{
+			name:    "short span id",
+			value:   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa-01",
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			value:   "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := trace.ParseTraceParent(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTraceParent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && tt.value[:2] == "00" && got.TraceParent() != tt.value {
+				t.Errorf("TraceParent() = %v, want %v", got.TraceParent(), tt.value)
+			}
+		})
+	}
+}
+
+func TestJSONLinesContinuesExtractedTrace(t *testing.T) {
+	header := http.Header{}
+	header.Set(
+		trace.TraceParentHeader,
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	)
+	ctx := trace.Extract(context.Background(), header)
+
+	var buf bytes.Buffer
+	tracer := trace.JSONLines(&buf)
+	ctx, parent := tracer.Start(ctx, "parent")
+	_, child := tracer.Start(ctx, "child")
+	child.SetAttribute("solutions", 2)
+	child.RecordError(errors.New("boom"))
+	child.End()
+	parent.End()
+
+	type record struct {
+		Name         string         `json:"name"`
+		TraceID      string         `json:"trace_id"`
+		SpanID       string         `json:"span_id"`
+		ParentSpanID string         `json:"parent_span_id"`
+		Attributes   map[string]any `json:"attributes"`
+		Error        string         `json:"error"`
+	}
+	var records []record
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var r record
+		if err := decoder.Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, r)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d spans; want 2", len(records))
+	}
+	childRecord, parentRecord := records[0], records[1]
+	for _, r := range records {
+		if r.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("span %s has trace id %s", r.Name, r.TraceID)
+		}
+	}
+	if parentRecord.ParentSpanID != "00f067aa0ba902b7" {
+		t.Errorf("got parent span id %s; want remote span id",
+			parentRecord.ParentSpanID)
+	}
+	if childRecord.ParentSpanID != parentRecord.SpanID {
+		t.Errorf("got parent span id %s; want %s",
+			childRecord.ParentSpanID, parentRecord.SpanID)
+	}
+	if childRecord.Error != "boom" || childRecord.Attributes["solutions"] != 2.0 {
+		t.Errorf("unexpected child span %+v", childRecord)
+	}
+}
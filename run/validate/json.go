@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -85,3 +86,54 @@ func (j JSONValidator[Input]) Validate(_ context.Context, input any) (retErr err
 	}
 	return nil
 }
+
+// OutputJSON creates a validator of solutions. The solution is encoded as JSON
+// and validated against the schema. If nil is passed as schema, the schema is
+// generated from the Solution type. An error is returned, if the schema is
+// invalid.
+func OutputJSON[Solution any](schema []byte) (
+	func(_ context.Context, solution any) error, error,
+) {
+	if len(schema) == 0 {
+		// generate schema for solution struct
+		s, err := humaSchema.Generate(reflect.TypeOf(new(Solution)))
+		if err != nil {
+			return nil, err
+		}
+		if schema, err = json.Marshal(s); err != nil {
+			return nil, err
+		}
+	}
+	loaded, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(schema))
+	if err != nil {
+		return nil, fmt.Errorf("loading output schema: %w", err)
+	}
+	return OutputJSONValidator{schema: loaded}.Validate, nil
+}
+
+// OutputJSONValidator validates solutions against a JSON schema.
+type OutputJSONValidator struct {
+	schema *gojsonschema.Schema
+}
+
+// Validate validates the solution against the JSON schema.
+func (v OutputJSONValidator) Validate(
+	_ context.Context, solution any,
+) error {
+	data, err := json.Marshal(solution)
+	if err != nil {
+		return err
+	}
+	result, err := v.schema.Validate(gojsonschema.NewBytesLoader(data))
+	if err != nil {
+		return err
+	}
+	if !result.Valid() {
+		descriptions := make([]string, len(result.Errors()))
+		for i, desc := range result.Errors() {
+			descriptions[i] = desc.String()
+		}
+		return errors.New(strings.Join(descriptions, "; "))
+	}
+	return nil
+}
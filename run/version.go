@@ -0,0 +1,39 @@
+package run
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+
+	"github.com/nextmv-io/sdk/run/schema"
+)
+
+// versionPath is the path of the version endpoint of the HTTPRunner. It
+// responds with the build information of the application, see
+// schema.ReadBuildInfo, to authenticated requests.
+const versionPath = "/version"
+
+// printVersion prints the build information of the application as JSON to
+// stdout, if the runner configuration asks for it, see VersionPrinter. It
+// returns whether the version was printed, in which case the runner does not
+// run.
+func printVersion(runnerConfig any) (bool, error) {
+	printer, ok := runnerConfig.(VersionPrinter)
+	if !ok || !printer.PrintVersion() {
+		return false, nil
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return true, encoder.Encode(schema.ReadBuildInfo())
+}
+
+// serveVersion writes the build information of the application.
+func serveVersion(w http.ResponseWriter, req *http.Request) error {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(schema.ReadBuildInfo())
+}
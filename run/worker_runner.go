@@ -0,0 +1,438 @@
+package run
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/nextmv-io/sdk/run/decode"
+	"github.com/nextmv-io/sdk/run/encode"
+	"github.com/nextmv-io/sdk/run/validate"
+)
+
+// Methods of the JSON-RPC 2.0 protocol spoken by the WorkerRunner.
+const (
+	// WorkerMethodSolve runs the algorithm. Its params are an object holding
+	// the "input" and optionally the "options" of the run and an
+	// "initial_solution" to warm-start the algorithm with. The result is the
+	// encoded output of the run.
+	WorkerMethodSolve = "solve"
+	// WorkerMethodCancel cancels a running solve request. Its params are an
+	// object holding the "id" of the solve request. The result reports
+	// whether the request was running.
+	WorkerMethodCancel = "cancel"
+	// WorkerMethodStatus reports the state of a solve request. Its params are
+	// an object holding the "id" of the solve request. Without params the ids
+	// of all running requests are reported.
+	WorkerMethodStatus = "status"
+	// WorkerMethodSolution is the notification sent for every solution the
+	// algorithm returns. Its params hold the "id" of the solve request and the
+	// "solution".
+	WorkerMethodSolution = "solution"
+)
+
+// JSON-RPC 2.0 error codes used by the WorkerRunner.
+const (
+	jsonRPCParseError     = -32700
+	jsonRPCInvalidRequest = -32600
+	jsonRPCMethodNotFound = -32601
+	jsonRPCInvalidParams  = -32602
+	jsonRPCServerError    = -32000
+)
+
+// NewWorkerRunner creates a runner that keeps the process alive and reads
+// newline-delimited JSON-RPC 2.0 requests from stdin until it is closed.
+// Every solve request is run through the same decoder, validator, algorithm
+// and encoder stages as in the CLIRunner. Responses, and every solution as a
+// notification, are written to stdout.
+func NewWorkerRunner[Input, Option, Solution any](
+	algorithm Algorithm[Input, Option, Solution],
+	options ...RunnerOption[WorkerRunnerConfig, Input, Option, Solution],
+) Runner[WorkerRunnerConfig, Input, Option, Solution] {
+	runner := &workerRunner[Input, Option, Solution]{
+		// the IOProducer is set for every solve request.
+		Runner: GenericRunner[WorkerRunnerConfig](
+			nil,
+			GenericDecoder[Input](decode.JSON()),
+			validate.JSON[Input](nil),
+			GenericDecoder[Option](decode.JSON()),
+			algorithm,
+			GenericEncoder[Solution, Option](encode.JSON()),
+		),
+		reader:  os.Stdin,
+		writer:  os.Stdout,
+		running: map[string]context.CancelCauseFunc{},
+	}
+
+	// the options configure the stages, so they apply to the generic runner.
+	for _, option := range options {
+		option(runner.Runner)
+	}
+
+	return runner
+}
+
+type workerRunner[Input, Option, Solution any] struct {
+	Runner[WorkerRunnerConfig, Input, Option, Solution]
+	reader io.Reader
+	writer io.Writer
+	// writeMutex serializes the messages written to the writer.
+	writeMutex sync.Mutex
+	// mutex guards running.
+	mutex   sync.Mutex
+	running map[string]context.CancelCauseFunc
+}
+
+type jsonRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type jsonRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *jsonRPCError   `json:"error,omitempty"`
+}
+
+type jsonRPCNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type workerSolveParams struct {
+	Input           json.RawMessage `json:"input"`
+	Options         json.RawMessage `json:"options,omitempty"`
+	InitialSolution json.RawMessage `json:"initial_solution,omitempty"`
+}
+
+type workerIDParams struct {
+	ID json.RawMessage `json:"id"`
+}
+
+type workerSolutionParams struct {
+	ID       json.RawMessage `json:"id"`
+	Solution any             `json:"solution"`
+}
+
+// Run reads requests until the reader is closed and waits for the running
+// solve requests to finish.
+func (w *workerRunner[Input, Option, Solution]) Run(
+	ctx context.Context,
+) (retErr error) {
+	// the tracer is closed once the running requests are finished.
+	defer func() {
+		err := closeTracer(w.Runner)
+		// the first error is more important
+		if retErr == nil {
+			retErr = err
+		}
+	}()
+	if printed, err := printVersion(w.Runner.RunnerConfig()); printed {
+		return err
+	}
+	slots := make(
+		chan struct{}, max(w.Runner.RunnerConfig().Runner.Worker.MaxParallel, 1),
+	)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	scanner := bufio.NewScanner(w.reader)
+	// requests carry the whole input, so allow long lines.
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<30)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var request jsonRPCRequest
+		if err := json.Unmarshal(line, &request); err != nil {
+			w.respondError(nil, jsonRPCParseError, err)
+			continue
+		}
+		if request.JSONRPC != "2.0" || request.Method == "" {
+			w.respondError(request.ID, jsonRPCInvalidRequest,
+				errors.New(`request must have jsonrpc "2.0" and a method`))
+			continue
+		}
+
+		switch request.Method {
+		case WorkerMethodSolve:
+			var params workerSolveParams
+			if err := json.Unmarshal(request.Params, &params); err != nil ||
+				len(params.Input) == 0 {
+				w.respondError(request.ID, jsonRPCInvalidParams,
+					errors.New("solve requires an input"))
+				continue
+			}
+			select {
+			case slots <- struct{}{}:
+			default:
+				w.respondError(request.ID, jsonRPCServerError,
+					errors.New("max number of parallel requests exceeded"))
+				continue
+			}
+			runCtx, cancel := context.WithCancelCause(ctx)
+			if !w.track(request.ID, cancel) {
+				cancel(nil)
+				<-slots
+				w.respondError(request.ID, jsonRPCInvalidRequest,
+					fmt.Errorf("request %s is already running", request.ID))
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-slots }()
+				defer w.untrack(request.ID)
+				defer cancel(nil)
+				w.solve(runCtx, request.ID, params)
+			}()
+		case WorkerMethodCancel:
+			var params workerIDParams
+			if err := json.Unmarshal(request.Params, &params); err != nil ||
+				len(params.ID) == 0 {
+				w.respondError(request.ID, jsonRPCInvalidParams,
+					errors.New("cancel requires the id of a solve request"))
+				continue
+			}
+			w.respond(request.ID, w.cancel(params.ID))
+		case WorkerMethodStatus:
+			var params workerIDParams
+			if len(request.Params) > 0 {
+				if err := json.Unmarshal(request.Params, &params); err != nil {
+					w.respondError(request.ID, jsonRPCInvalidParams, err)
+					continue
+				}
+			}
+			w.respond(request.ID, w.status(params.ID))
+		default:
+			w.respondError(request.ID, jsonRPCMethodNotFound,
+				fmt.Errorf("method %q not found", request.Method))
+		}
+	}
+	return scanner.Err()
+}
+
+// solve runs a copy of the underlying runner for a single solve request.
+func (w *workerRunner[Input, Option, Solution]) solve(
+	ctx context.Context, id json.RawMessage, params workerSolveParams,
+) {
+	options := params.Options
+	if len(options) == 0 || bytes.Equal(options, []byte("null")) {
+		// fall back to the options configured via flags
+		options = []byte("{}")
+	}
+	output := &bytes.Buffer{}
+	var ioOptions []IODataOption
+	if len(params.InitialSolution) > 0 {
+		ioOptions = append(ioOptions,
+			WithInitialSolution(bytes.NewReader(params.InitialSolution)),
+		)
+	}
+
+	runner := cloneRunner(w.Runner)
+	runner.SetIOProducer(
+		func(context.Context, WorkerRunnerConfig) (IOData, error) {
+			return NewIOData(
+				bytes.NewReader(params.Input),
+				bytes.NewReader(options),
+				output,
+				ioOptions...,
+			)
+		},
+	)
+	ctx = withSolutionObserver(ctx, w.notify(id))
+	if err := runner.Run(ctx); err != nil {
+		w.respondError(id, jsonRPCServerError, err)
+		return
+	}
+	w.respond(id, encodedResult(output.Bytes()))
+}
+
+// notify returns the observer sending every solution the runner accepts as a
+// notification. The solution of a notification is encoded with the encoder of
+// the runner, like the result.
+func (w *workerRunner[Input, Option, Solution]) notify(
+	id json.RawMessage,
+) solutionObserver {
+	return func(ctx context.Context, solution, option any) error {
+		typedSolution, _ := solution.(Solution)
+		typedOption, _ := option.(Option)
+		encoded, err := w.encodeSolution(ctx, typedSolution, typedOption)
+		if err != nil {
+			return err
+		}
+		w.write(jsonRPCNotification{
+			JSONRPC: "2.0",
+			Method:  WorkerMethodSolution,
+			Params: workerSolutionParams{
+				ID: id, Solution: encodedResult(encoded),
+			},
+		})
+		return nil
+	}
+}
+
+// encodeSolution encodes the solution with the encoder of the runner.
+func (w *workerRunner[Input, Option, Solution]) encodeSolution(
+	ctx context.Context, solution Solution, option Option,
+) ([]byte, error) {
+	single := make(chan Solution, 1)
+	single <- solution
+	close(single)
+	var encoded bytes.Buffer
+	err := w.Runner.GetEncoder().Encode(
+		ctx, single, &encoded, w.Runner.RunnerConfig(), option,
+	)
+	return encoded.Bytes(), err
+}
+
+// encodedResult turns the encoded output into the result of a solve request.
+// JSON outputs are embedded as is, several solutions become an array. Other
+// outputs are embedded as a string.
+func encodedResult(output []byte) any {
+	var values []json.RawMessage
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	for {
+		var value json.RawMessage
+		err := decoder.Decode(&value)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return string(output)
+		}
+		values = append(values, value)
+	}
+	switch len(values) {
+	case 0:
+		return nil
+	case 1:
+		return values[0]
+	default:
+		return values
+	}
+}
+
+func (w *workerRunner[Input, Option, Solution]) track(
+	id json.RawMessage, cancel context.CancelCauseFunc,
+) bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	key := string(compactJSON(id))
+	if _, ok := w.running[key]; ok {
+		return false
+	}
+	w.running[key] = cancel
+	return true
+}
+
+func (w *workerRunner[Input, Option, Solution]) untrack(id json.RawMessage) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	delete(w.running, string(compactJSON(id)))
+}
+
+// cancel cancels the solve request with the given id and reports whether it
+// was running. The request still responds with the last solution found.
+func (w *workerRunner[Input, Option, Solution]) cancel(
+	id json.RawMessage,
+) bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	cancel, ok := w.running[string(compactJSON(id))]
+	if ok {
+		cancel(TerminationCancelled)
+	}
+	return ok
+}
+
+func (w *workerRunner[Input, Option, Solution]) status(
+	id json.RawMessage,
+) any {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if len(id) == 0 {
+		running := make([]json.RawMessage, 0, len(w.running))
+		for id := range w.running {
+			running = append(running, json.RawMessage(id))
+		}
+		return map[string]any{"running": running}
+	}
+	state := "unknown"
+	if _, ok := w.running[string(compactJSON(id))]; ok {
+		state = "running"
+	}
+	return map[string]any{"id": id, "state": state}
+}
+
+// compactJSON removes insignificant whitespace, so ids can be compared.
+func compactJSON(raw json.RawMessage) json.RawMessage {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return raw
+	}
+	return buf.Bytes()
+}
+
+func (w *workerRunner[Input, Option, Solution]) respond(
+	id json.RawMessage, result any,
+) {
+	// notifications do not get a response
+	if len(id) == 0 {
+		return
+	}
+	if result == nil {
+		result = json.RawMessage("null")
+	}
+	w.write(jsonRPCResponse{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+func (w *workerRunner[Input, Option, Solution]) respondError(
+	id json.RawMessage, code int, err error,
+) {
+	if len(id) == 0 {
+		// errors for unparsable requests are responded with a null id
+		if code != jsonRPCParseError && code != jsonRPCInvalidRequest {
+			return
+		}
+		id = json.RawMessage("null")
+	}
+	w.write(jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   &jsonRPCError{Code: code, Message: err.Error()},
+	})
+}
+
+func (w *workerRunner[Input, Option, Solution]) write(message any) {
+	b, err := json.Marshal(message)
+	if err != nil {
+		b, _ = json.Marshal(jsonRPCResponse{
+			JSONRPC: "2.0",
+			ID:      json.RawMessage("null"),
+			Error: &jsonRPCError{
+				Code: jsonRPCServerError, Message: err.Error(),
+			},
+		})
+	}
+	w.writeMutex.Lock()
+	defer w.writeMutex.Unlock()
+	// a closed stdout cannot be reported anywhere else
+	_, _ = w.writer.Write(append(b, '\n'))
+}
@@ -0,0 +1,67 @@
+package run
+
+// WorkerRunnerConfig defines the configuration of the WorkerRunner.
+type WorkerRunnerConfig struct {
+	Runner struct {
+		Seed    int64 `usage:"The seed of the random number generators of a run, generated if 0"`
+		Version bool  `usage:"Print the build information as JSON and exit"`
+		Output  struct {
+			Solutions  string `default:"last" usage:"{all, last}"`
+			Validation string `default:"off" usage:"{off, warn, fail}, whether solutions are validated against the output schema and invalid ones are logged or fail the run"`
+			Schema     string `usage:"The file path of the JSON schema solutions are validated against, generated from the solution type if empty"`
+		}
+		Worker struct {
+			MaxParallel int `default:"1" usage:"The max number of solve requests run in parallel"`
+		}
+		Tracing struct {
+			Path string `usage:"The file path spans are written to as JSON lines"`
+		}
+		Limits struct {
+			Memory    int64 `usage:"The soft memory limit of the process in bytes, exceeding it terminates the active runs (0 means no limit)"`
+			Solutions int   `usage:"The max number of solutions accepted from the algorithm (0 means no limit)"`
+		}
+	}
+}
+
+// Solutions returns the configured solutions.
+func (c WorkerRunnerConfig) Solutions() (Solutions, error) {
+	return ParseSolutions(c.Runner.Output.Solutions)
+}
+
+// TracingPath returns the tracing path.
+func (c WorkerRunnerConfig) TracingPath() string {
+	return c.Runner.Tracing.Path
+}
+
+// MemoryLimit returns the soft memory limit in bytes.
+func (c WorkerRunnerConfig) MemoryLimit() int64 {
+	return c.Runner.Limits.Memory
+}
+
+// MaxSolutions returns the max number of solutions accepted from the
+// algorithm.
+func (c WorkerRunnerConfig) MaxSolutions() int {
+	return c.Runner.Limits.Solutions
+}
+
+// OutputValidation returns how solutions are validated.
+func (c WorkerRunnerConfig) OutputValidation() (Validation, error) {
+	return ParseValidation(c.Runner.Output.Validation)
+}
+
+// OutputSchemaPath returns the path of the schema solutions are validated
+// against.
+func (c WorkerRunnerConfig) OutputSchemaPath() string {
+	return c.Runner.Output.Schema
+}
+
+// PrintVersion returns whether to print the build information instead of
+// running.
+func (c WorkerRunnerConfig) PrintVersion() bool {
+	return c.Runner.Version
+}
+
+// Seed returns the seed of the random number generators of a run.
+func (c WorkerRunnerConfig) Seed() int64 {
+	return c.Runner.Seed
+}